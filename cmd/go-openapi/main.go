@@ -0,0 +1,120 @@
+// Command go-openapi works with OpenAPI models and specifications.
+//
+// Usage:
+//
+//	go-openapi fake -models <path> [-include <path:glob>]... [-spec <file>] -type <Component> [-seed <n>] [-count <n>]
+//
+// The _fake_ subcommand prints random, but valid, json values of a component, one per line,
+// see package _generator/fake_.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/fake"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: go-openapi <command> [arguments], commands: fake")
+	}
+
+	switch args[0] {
+	case "fake":
+		return runFake(args[1:], out)
+	}
+
+	return fmt.Errorf("unknown command: %s, commands: fake", args[0])
+}
+
+// includes is a repeatable _-include_ flag.
+type includes []string
+
+func (i *includes) String() string {
+	return strings.Join(*i, ",")
+}
+
+func (i *includes) Set(value string) error {
+	*i = append(*i, value)
+	return nil
+}
+
+func runFake(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("fake", flag.ContinueOnError)
+
+	models := flags.String("models", "", "the path of the model files")
+	spec := flags.String("spec", "", "the OpenAPI specification file")
+	name := flags.String("type", "", "the component to produce values of, lists the components when empty")
+	seed := flags.Int64("seed", 1, "the seed of the random values")
+	count := flags.Int("count", 1, "the number of values to produce")
+	depth := flags.Int("depth", fake.DefaultMaxDepth, "the depth where optional properties are no longer produced")
+
+	var include includes
+	flags.Var(&include, "include", "a path, relative to models, and an optional glob e.g. pets:*.yaml")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	settings := generator.NewSettings(generator.Templates{})
+
+	if *models != "" {
+		path, err := filepath.Abs(*models)
+		if err != nil {
+			return err
+		}
+
+		settings.UseModelPath(path, "models").Include(include...)
+	}
+
+	if *spec != "" {
+		path, err := filepath.Abs(*spec)
+		if err != nil {
+			return err
+		}
+
+		settings.UseSpec(path, "spec")
+	}
+
+	ctx := &generator.GeneratorContext{}
+	if err := settings.ToGenerator().Generate(ctx); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		fmt.Fprintln(out, strings.Join(fake.Names(ctx), "\n"))
+		return nil
+	}
+
+	faker := fake.New(ctx, *seed).UseMaxDepth(*depth)
+
+	for i := 0; i < *count; i++ {
+		value, err := faker.Component(*name)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, string(data))
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// HandleAdditionalProperties will set the `TypeDefinition.AdditionalProperties` when _def_
+// declares _additionalProperties_, either as a schema or as `true`.
+//
+// When _def_ has no properties, the _td_ is a map (see `TypeDefinition.IsMap`), otherwise it
+// is an object with declared properties that also captures any unknown properties.
+//
+// NOTE: `additionalProperties: true` will be values of any type.
+func HandleAdditionalProperties(
+	ctx *GeneratorContext,
+	td *gentypes.TypeDefinition,
+	component *gentypes.ComponentDefinition,
+	def *openapi3.Schema) error {
+
+	if def.Type != "" && def.Type != "object" {
+		return nil
+	}
+
+	values_id := component.ID.NewWithAppendTypeName("AdditionalProperties")
+
+	if def.AdditionalProperties != nil {
+		values, err := CreateInlineComponent(ctx, values_id, def.AdditionalProperties)
+		if err != nil {
+			return err
+		}
+
+		td.AdditionalProperties = values
+		return nil
+	}
+
+	if def.AdditionalPropertiesAllowed == nil || !*def.AdditionalPropertiesAllowed {
+		return nil
+	}
+
+	// Any value
+	td.AdditionalProperties = &gentypes.ComponentDefinition{
+		ID: *values_id,
+		Definition: &gentypes.TypeDefinition{
+			ID:          *values_id,
+			GoPackage:   td.GoPackage,
+			Schema:      &openapi3.Schema{},
+			Composition: []gentypes.Composition{},
+			Properties:  []gentypes.Property{},
+			Inline:      true,
+		},
+	}
+
+	return nil
+}
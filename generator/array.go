@@ -5,26 +5,31 @@ import (
 	"github.com/mariotoffia/go-openapi/generator/gentypes"
 )
 
-func HandleArray(ctx *GeneratorContext, arrayId *gentypes.ComponentReference, items *openapi3.SchemaRef) (*gentypes.ComponentDefinition, error) {
-	if ctx.resolver.ResolveComponent(arrayId) != nil {
-		return nil, nil
-	}
+// HandleArray creates the component for the array _items_ and registers it as _itemsId_, see
+// `CreateInlineComponent`.
+//
+// Nested arrays, e.g. `[][]T`, are handled recursively since the items are created as any other
+// component.
+func HandleArray(ctx *GeneratorContext, itemsId *gentypes.ComponentReference, items *openapi3.SchemaRef) (*gentypes.ComponentDefinition, error) {
+	return CreateInlineComponent(ctx, itemsId, items)
+}
 
-	if IsReference(items) || items.Value.Type == "object" {
-		// Array is ref or an array of objects -> create as reference
-		ref, err := CreateComponentFromReference(ctx, arrayId, items)
-		if err != nil {
-			return nil, err
-		}
+// HandleArrayDefinition will set the `TypeDefinition.Items` when _td_ is an array.
+func HandleArrayDefinition(
+	ctx *GeneratorContext,
+	td *gentypes.TypeDefinition,
+	component *gentypes.ComponentDefinition,
+	def *openapi3.Schema) error {
 
-		return ref, nil
+	if def.Type != "array" || def.Items == nil {
+		return nil
 	}
 
-	// No object, this is a definition of a primitive type
-	def, err := CreateComponentFromDefinition(ctx, arrayId, items.Value)
+	items, err := HandleArray(ctx, component.ID.NewWithAppendTypeName("Item"), def.Items)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return def, nil
+	td.Items = items
+	return nil
 }
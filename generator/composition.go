@@ -1,10 +1,18 @@
 package generator
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mariotoffia/go-openapi/generator/gentypes"
 )
 
+// ExtensionGoInline is the extension that flattens _allOf_ members into the composing
+// struct instead of embedding them. When set on the composing schema all members are
+// flattened, when set on a member schema that member is always flattened.
+const ExtensionGoInline = "x-go-inline"
+
 func HandleComposition(
 	ctx *GeneratorContext,
 	td *gentypes.TypeDefinition,
@@ -16,8 +24,6 @@ func HandleComposition(
 	}
 
 	// Extract *all* inline objects first
-	var err error
-
 	for {
 
 		inline_objects := openapi3.SchemaRefs{}
@@ -43,22 +49,36 @@ func HandleComposition(
 		// Merge the inline objects into the main schema.
 		for i := range inline_objects {
 			c := inline_objects[i]
-			if td.Schema, err = MergeSchemaObjects(ctx, td.Schema, c.Value); err != nil {
+			merged, err := MergeSchemaObjects(ctx, td.Schema, c.Value)
+
+			var unsatisfiable *UnsatisfiableError
+			if errors.As(err, &unsatisfiable) {
+				// Keep generating with the constraints of the first member
+				ctx.AddDiagnosticAt(&component.ID, ctx.Locate(&component.ID, "/allOf"), "%s", unsatisfiable.Error())
+			} else if err != nil {
 				return err
 			}
+
+			td.Schema = merged
 		}
 
 	}
 
 	// Handle all references (all inline objects have been removed)
 	for i := range def.AllOf {
-		compose_type_id := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, def.AllOf[i])
+		compose_type_id, err := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, def.AllOf[i])
+		if err != nil {
+			return err
+		}
 
-		if ctx.resolver.ResolveComponent(compose_type_id) == nil {
-			// Ensure reference is created.
-			if _, err = CreateComponentFromReference(ctx, compose_type_id, def.AllOf[i]); err != nil {
-				return err
-			}
+		// Ensure reference is created.
+		if err = CreateReferencedComponent(ctx, compose_type_id, def.AllOf[i]); err != nil {
+			return err
+		}
+
+		inline, _ := GetExtensionBool(def, ExtensionGoInline)
+		if !inline {
+			inline, _ = GetExtensionBool(def.AllOf[i].Value, ExtensionGoInline)
 		}
 
 		td.Composition = append(td.Composition, gentypes.Composition{
@@ -66,9 +86,103 @@ func HandleComposition(
 				ID:        *compose_type_id.NewWithAppendTypeName("Composition"),
 				Reference: compose_type_id,
 			},
-			Inline: false,
+			Inline: inline,
 		})
 	}
 
 	return nil
 }
+
+// CheckCompositions verifies that the members of each _allOf_ composition, including the
+// properties of the composing type, do not declare the same property with conflicting types.
+func CheckCompositions(ctx *GeneratorContext) error {
+	type declared struct {
+		signature string
+		from      *gentypes.TypeDefinition
+	}
+
+	for _, component := range ctx.resolver.Components() {
+		td := ctx.ResolveDefinition(component)
+		if td == nil || len(td.Composition) == 0 {
+			continue
+		}
+
+		properties := map[string]declared{}
+
+		var collect func(member *gentypes.TypeDefinition) error
+		collect = func(member *gentypes.TypeDefinition) error {
+			for i := range member.Composition {
+				composed := ctx.ResolveDefinition(&member.Composition[i].ComponentDefinition)
+				if composed == nil {
+					return fmt.Errorf("could not resolve composition: %s", member.Composition[i].ID.String())
+				}
+
+				if err := collect(composed); err != nil {
+					return err
+				}
+			}
+
+			for i := range member.Properties {
+				property := &member.Properties[i]
+
+				property_td := ctx.ResolveDefinition(&property.ComponentDefinition)
+				if property_td == nil {
+					return fmt.Errorf("could not resolve property: %s", property.ID.String())
+				}
+
+				signature := TypeSignature(ctx, property_td)
+
+				if existing, ok := properties[property.PropertyName]; ok &&
+					existing.signature != signature &&
+					existing.signature != anySignature && signature != anySignature {
+
+					return fmt.Errorf(
+						"property: %s in composition: %s has conflicting types: %s (%s) and %s (%s)",
+						property.PropertyName, td.ID.String(),
+						existing.signature, existing.from.ID.String(),
+						signature, member.ID.String(),
+					)
+				}
+
+				properties[property.PropertyName] = declared{signature: signature, from: member}
+			}
+
+			return nil
+		}
+
+		if err := collect(td); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// anySignature is the signature of a type that accepts any value.
+const anySignature = "any"
+
+// TypeSignature returns a string that identifies the shape of _td_. Named objects and
+// unions are identified by their id, inline ones are all alike. Primitives are identified
+// by their go type, hence e.g. a string enum is compatible with a string.
+func TypeSignature(ctx *GeneratorContext, td *gentypes.TypeDefinition) string {
+	switch {
+	case td.IsArray():
+		if items := ctx.ResolveDefinition(td.Items); items != nil {
+			return "[]" + TypeSignature(ctx, items)
+		}
+	case td.IsMap():
+		if values := ctx.ResolveDefinition(td.AdditionalProperties); values != nil {
+			return "map[string]" + TypeSignature(ctx, values)
+		}
+	case td.IsPrimitive():
+		return td.GoType.Name
+	case td.IsObject(), td.IsUnion():
+		if td.Inline {
+			return "object"
+		}
+
+		return td.ID.String()
+	}
+
+	return anySignature
+}
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// Cycle is a reference cycle between type definitions, e.g. a tree node that has
+// children of the same type or two components referencing each other.
+type Cycle struct {
+	// Components are the type definitions in the cycle in reference order. The last
+	// references the first.
+	Components []gentypes.ComponentReference
+	// Pointer is the property that is rendered as a pointer in order to break the
+	// cycle. It is `nil` when the cycle already is broken by an array, a map, a
+	// pointer property, see `PropertyPresence`, a union or the pointer of a previous
+	// cycle.
+	Pointer *gentypes.Property
+}
+
+// String renders the cycle as e.g. _tree/tree#/Node -> tree/tree#/Node_.
+func (c Cycle) String() string {
+	names := make([]string, 0, len(c.Components)+1)
+	for i := range c.Components {
+		names = append(names, displayName(&c.Components[i]))
+	}
+
+	if len(c.Components) > 0 {
+		names = append(names, displayName(&c.Components[0]))
+	}
+
+	return strings.Join(names, " -> ")
+}
+
+// cycleEdge is a reference from one type definition to another.
+type cycleEdge struct {
+	to *gentypes.TypeDefinition
+	// property is set when the edge is a property.
+	property *gentypes.Property
+	// direct is set when the referenced type is embedded by value and
+	// hence the edge do not break a cycle.
+	direct bool
+}
+
+func (e cycleEdge) isDirect() bool {
+	return e.direct && (e.property == nil || !e.property.Pointer)
+}
+
+// DetectCycles finds the reference cycles among all type definitions.
+//
+// The type definitions are partitioned into strongly connected components, i.e. sets of
+// types that all reach each other, and all elementary cycles within each set are reported.
+//
+// When a cycle only consists of types embedded by value it can not be represented in
+// go. The first property in such cycle is marked as `Property.Pointer`. The cycles are
+// broken in order, hence a cycle may already be broken by the pointer of a previous cycle.
+// If the cycle has no properties, i.e. it is a cycle of _allOf_ compositions, an error is
+// returned.
+//
+// The cycles may span model files since these are loaded by the `ModelLoader`.
+func DetectCycles(ctx *GeneratorContext) ([]Cycle, error) {
+	definitions := []*gentypes.TypeDefinition{}
+	for _, component := range ctx.resolver.Components() {
+		if td := ctx.ResolveDefinition(component); td != nil {
+			definitions = append(definitions, td)
+		}
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID.String() < definitions[j].ID.String()
+	})
+
+	// The edges are resolved once, also for inline types that are not registered
+	cache := map[string][]cycleEdge{}
+	edges := func(td *gentypes.TypeDefinition) []cycleEdge {
+		id := td.ID.String()
+		if _, ok := cache[id]; !ok {
+			cache[id] = cycleEdges(ctx, td)
+		}
+
+		return cache[id]
+	}
+
+	result := []Cycle{}
+
+	for _, scc := range stronglyConnected(definitions, edges) {
+		for _, c := range elementaryCycles(scc, edges) {
+			broken := false
+			for _, edge := range c.edges {
+				if !edge.isDirect() {
+					broken = true
+					break
+				}
+			}
+
+			if !broken {
+				for _, edge := range c.edges {
+					if edge.property != nil {
+						edge.property.Pointer = true
+						c.cycle.Pointer = edge.property
+						break
+					}
+				}
+
+				if c.cycle.Pointer == nil {
+					return nil, fmt.Errorf("composition cycle can not be represented in go: %s", c.cycle.String())
+				}
+			}
+
+			result = append(result, c.cycle)
+		}
+	}
+
+	return result, nil
+}
+
+// foundCycle is a cycle and the edges that it consists of.
+type foundCycle struct {
+	cycle Cycle
+	edges []cycleEdge
+}
+
+// stronglyConnected returns the strongly connected components, using Tarjan's algorithm,
+// that has a cycle, i.e. more than one type or a type that references itself. The types of
+// each component are sorted on id and the components on their first type.
+func stronglyConnected(
+	definitions []*gentypes.TypeDefinition,
+	edges func(td *gentypes.TypeDefinition) []cycleEdge) [][]*gentypes.TypeDefinition {
+
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []*gentypes.TypeDefinition{}
+	result := [][]*gentypes.TypeDefinition{}
+
+	var connect func(td *gentypes.TypeDefinition)
+	connect = func(td *gentypes.TypeDefinition) {
+		id := td.ID.String()
+		index[id] = len(index)
+		lowlink[id] = index[id]
+		stack = append(stack, td)
+		onStack[id] = true
+
+		for _, edge := range edges(td) {
+			to := edge.to.ID.String()
+
+			if _, visited := index[to]; !visited {
+				connect(edge.to)
+
+				if lowlink[to] < lowlink[id] {
+					lowlink[id] = lowlink[to]
+				}
+			} else if onStack[to] && index[to] < lowlink[id] {
+				lowlink[id] = index[to]
+			}
+		}
+
+		if lowlink[id] != index[id] {
+			return
+		}
+
+		scc := []*gentypes.TypeDefinition{}
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member.ID.String()] = false
+			scc = append(scc, member)
+
+			if member == td {
+				break
+			}
+		}
+
+		if len(scc) == 1 && !referencesItself(td, edges(td)) {
+			return
+		}
+
+		sort.Slice(scc, func(i, j int) bool {
+			return scc[i].ID.String() < scc[j].ID.String()
+		})
+
+		result = append(result, scc)
+	}
+
+	for _, td := range definitions {
+		if _, visited := index[td.ID.String()]; !visited {
+			connect(td)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0].ID.String() < result[j][0].ID.String()
+	})
+
+	return result
+}
+
+// referencesItself returns `true` if one of the _edges_ of _td_ is to _td_.
+func referencesItself(td *gentypes.TypeDefinition, edges []cycleEdge) bool {
+	for _, edge := range edges {
+		if edge.to.ID.String() == td.ID.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// elementaryCycles returns all cycles in the strongly connected component _scc_ that do not
+// visit a type more than once. Each cycle starts at its type with the lowest id and the
+// edges are followed in declaration order.
+func elementaryCycles(
+	scc []*gentypes.TypeDefinition,
+	edges func(td *gentypes.TypeDefinition) []cycleEdge) []foundCycle {
+
+	order := map[string]int{}
+	for i, td := range scc {
+		order[td.ID.String()] = i
+	}
+
+	cycles := []foundCycle{}
+
+	for start := range scc {
+		path := []*gentypes.TypeDefinition{}
+		used := []cycleEdge{}
+		onPath := map[string]bool{}
+
+		var visit func(td *gentypes.TypeDefinition)
+		visit = func(td *gentypes.TypeDefinition) {
+			path = append(path, td)
+			onPath[td.ID.String()] = true
+
+			for _, edge := range edges(td) {
+				to := edge.to.ID.String()
+
+				// Only types in the component after the start, the others have been started from
+				if i, ok := order[to]; !ok || i < start {
+					continue
+				}
+
+				if i := order[to]; i == start {
+					c := foundCycle{edges: append(append([]cycleEdge{}, used...), edge)}
+					for _, member := range path {
+						c.cycle.Components = append(c.cycle.Components, member.ID)
+					}
+
+					cycles = append(cycles, c)
+				} else if !onPath[to] {
+					used = append(used, edge)
+					visit(edge.to)
+					used = used[:len(used)-1]
+				}
+			}
+
+			onPath[td.ID.String()] = false
+			path = path[:len(path)-1]
+		}
+
+		visit(scc[start])
+	}
+
+	return cycles
+}
+
+// cycleEdges returns all references from _td_ to other type definitions.
+func cycleEdges(ctx *GeneratorContext, td *gentypes.TypeDefinition) []cycleEdge {
+	edges := []cycleEdge{}
+
+	add := func(component *gentypes.ComponentDefinition, property *gentypes.Property, direct bool) {
+		if to := ctx.ResolveDefinition(component); to != nil {
+			edges = append(edges, cycleEdge{to: to, property: property, direct: direct})
+		}
+	}
+
+	for i := range td.Composition {
+		add(&td.Composition[i].ComponentDefinition, nil, true)
+	}
+
+	for i := range td.Properties {
+		property := &td.Properties[i]
+
+		direct := false
+		if to := ctx.ResolveDefinition(&property.ComponentDefinition); to != nil {
+			presence, err := PropertyPresence(ctx, property, to)
+			direct = err == nil && presence != PresencePointer && !IsNillable(to)
+		}
+
+		add(&property.ComponentDefinition, property, direct)
+	}
+
+	if td.Items != nil {
+		add(td.Items, nil, false)
+	}
+
+	if td.AdditionalProperties != nil {
+		add(td.AdditionalProperties, nil, false)
+	}
+
+	for i := range td.OneOf {
+		add(&td.OneOf[i], nil, false)
+	}
+
+	for i := range td.AnyOf {
+		add(&td.AnyOf[i], nil, false)
+	}
+
+	for i := range td.DiscriminatorComponents {
+		add(&td.DiscriminatorComponents[i].ComponentDefinition, nil, false)
+	}
+
+	return edges
+}
@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic is a problem in the specification that does not prevent the generation,
+// e.g. a type that no value can satisfy.
+type Diagnostic struct {
+	// Component is the component that the diagnostic is about.
+	Component gentypes.ComponentReference
+	// Message describes the problem.
+	Message string
+	// Location is where in the source the problem is. It is `nil` when not known.
+	Location *Location
+}
+
+// Location is a position in a specification or model file.
+type Location struct {
+	// File is the path of the file relative to the root path.
+	File string
+	// Pointer is the json pointer within the file, e.g. _/Pet/properties/name/example_.
+	Pointer string
+	// Line is the line, starting at one. It is zero when the position is not known.
+	Line int
+	// Column is the column, starting at one.
+	Column int
+}
+
+// String renders the location as e.g. _pets/pets.yaml:12:7_ or, when the position is not
+// known, as _pets/pets.yaml#/Pet/example_.
+func (l Location) String() string {
+	if l.Line == 0 {
+		return l.File + "#" + l.Pointer
+	}
+
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// String renders the diagnostic as e.g. _pets/pets#/Pet: the message_, prefixed with the
+// location when known e.g. _pets/pets.yaml:12:7: pets/pets#/Pet: the message_.
+func (d Diagnostic) String() string {
+	if d.Location != nil {
+		return fmt.Sprintf("%s: %s: %s", d.Location.String(), displayName(&d.Component), d.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", displayName(&d.Component), d.Message)
+}
+
+// AddDiagnostic adds a diagnostic about the _component_.
+func (ctx *GeneratorContext) AddDiagnostic(component *gentypes.ComponentReference, format string, args ...any) {
+	ctx.diagnostics = append(ctx.diagnostics, Diagnostic{
+		Component: *component,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// AddDiagnosticAt adds a diagnostic about the _component_ at the _location_.
+func (ctx *GeneratorContext) AddDiagnosticAt(
+	component *gentypes.ComponentReference,
+	location *Location,
+	format string, args ...any) {
+
+	ctx.diagnostics = append(ctx.diagnostics, Diagnostic{
+		Component: *component,
+		Message:   fmt.Sprintf(format, args...),
+		Location:  location,
+	})
+}
+
+// GetDiagnostics returns the diagnostics from the last `Generator.Generate`.
+func (ctx *GeneratorContext) GetDiagnostics() []Diagnostic {
+	return ctx.diagnostics
+}
+
+// displayName renders the _ref_ relative to its root, e.g. _pets/pets#/Pet_.
+func displayName(ref *gentypes.ComponentReference) string {
+	return fmt.Sprintf(
+		"%s#/%s",
+		filepath.ToSlash(filepath.Join(ref.Path, ref.Module)),
+		filepath.ToSlash(filepath.Join(ref.NameSpace, ref.TypeName)),
+	)
+}
+
+// sourceExtensions are the file extensions that a module file may have.
+var sourceExtensions = []string{"yaml", "yml", "json"}
+
+// Locate returns the location of the json _pointer_ within the file of the component
+// _ref_. The _pointer_ is relative to the component, e.g. _/properties/name/example_.
+//
+// The line and column are looked up in the file. When a property is not found in a schema,
+// the _allOf_ members of it are searched since inline members are merged. If the pointer
+// can not be fully resolved, the position of the closest parent is used.
+func (ctx *GeneratorContext) Locate(ref *gentypes.ComponentReference, pointer string) *Location {
+	component := "/" + filepath.ToSlash(filepath.Join(ref.NameSpace, ref.TypeName))
+
+	for _, ext := range sourceExtensions {
+		path := ref.ToFqFilePath(ext)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		location := &Location{
+			File:    filepath.ToSlash(ref.ToRelativeFilePath(ext)),
+			Pointer: component + pointer,
+		}
+
+		if node := ctx.sourceNode(path); node != nil {
+			if found := findNode(node, pointerTokens(location.Pointer)); found != nil {
+				location.Line, location.Column = found.Line, found.Column
+			}
+		}
+
+		return location
+	}
+
+	return nil
+}
+
+// sourceNode returns the parsed yaml (or json) document of the file at _path_, or `nil`
+// when it can not be parsed. The documents are cached in the context.
+func (ctx *GeneratorContext) sourceNode(path string) *yaml.Node {
+	if node, ok := ctx.sources[path]; ok {
+		return node
+	}
+
+	if ctx.sources == nil {
+		ctx.sources = map[string]*yaml.Node{}
+	}
+
+	var node *yaml.Node
+	if data, err := os.ReadFile(path); err == nil {
+		var document yaml.Node
+		if yaml.Unmarshal(data, &document) == nil && len(document.Content) > 0 {
+			node = document.Content[0]
+		}
+	}
+
+	ctx.sources[path] = node
+	return node
+}
+
+// findNode returns the node at the json pointer _tokens_ from _node_, or the closest parent
+// found. The key is returned for mapping values since it is where the value is declared.
+func findNode(node *yaml.Node, tokens []string) *yaml.Node {
+	found, _ := walkNode(node, tokens)
+	return found
+}
+
+// walkNode returns the node at _tokens_ and `true`, or the closest parent and `false`.
+func walkNode(node *yaml.Node, tokens []string) (*yaml.Node, bool) {
+	if len(tokens) == 0 {
+		return node, true
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		closest := node
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != tokens[0] {
+				continue
+			}
+
+			if len(tokens) == 1 {
+				return node.Content[i], true
+			}
+
+			found, ok := walkNode(node.Content[i+1], tokens[1:])
+			if ok {
+				return found, true
+			}
+
+			closest = found
+		}
+
+		// Inline allOf members are merged into the composing schema
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != "allOf" || node.Content[i+1].Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for _, member := range node.Content[i+1].Content {
+				if found, ok := walkNode(member, tokens); ok {
+					return found, true
+				}
+			}
+		}
+
+		return closest, false
+	case yaml.SequenceNode:
+		if index, err := strconv.Atoi(tokens[0]); err == nil && index >= 0 && index < len(node.Content) {
+			return walkNode(node.Content[index], tokens[1:])
+		}
+	}
+
+	return node, false
+}
+
+// pointerTokens splits the json _pointer_ into its unescaped tokens.
+func pointerTokens(pointer string) []string {
+	tokens := []string{}
+
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token != "" {
+			tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+	}
+
+	return tokens
+}
+
+// EscapePointerToken escapes the _token_ to be used in a json pointer.
+func EscapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
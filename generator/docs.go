@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// TypeDoc renders the doc comment lines of the named type _name_ rendered from _td_.
+//
+// The first line states where the type is generated from, followed by the paragraphs of
+// the description, see `DescriptionDoc`, the example, the constraints and a `Deprecated:`
+// paragraph when the schema is _deprecated_.
+func TypeDoc(td *gentypes.TypeDefinition, name string) []string {
+	doc := []string{
+		fmt.Sprintf(
+			"%s is generated from %s#/%s.",
+			name, td.ID.RelativeModulePath(), filepath.Join(td.ID.NameSpace, td.ID.TypeName),
+		),
+	}
+
+	return appendSchemaDoc(doc, td.Schema, "type")
+}
+
+// FieldDoc renders the doc comment lines of the field rendered from _property_ of type _td_.
+//
+// Only inline property schemas are documented, a referenced type is documented on the type
+// itself.
+func FieldDoc(property *gentypes.Property, td *gentypes.TypeDefinition) []string {
+	if property.Reference != nil {
+		return nil
+	}
+
+	return appendSchemaDoc(nil, td.Schema, "property")
+}
+
+// appendSchemaDoc appends the description, example, constraints and deprecation of
+// _schema_ as paragraphs to _doc_. The _kind_ is used in the deprecation notice.
+func appendSchemaDoc(doc []string, schema *openapi3.Schema, kind string) []string {
+	if schema == nil {
+		return doc
+	}
+
+	paragraph := func(lines ...string) {
+		if len(doc) > 0 {
+			doc = append(doc, "")
+		}
+
+		doc = append(doc, lines...)
+	}
+
+	if description := DescriptionDoc(schema.Description); len(description) > 0 {
+		paragraph(description...)
+	}
+
+	if schema.Example != nil {
+		if example, err := json.Marshal(schema.Example); err == nil {
+			paragraph("Example: " + string(example))
+		}
+	}
+
+	if constraints := SchemaConstraints(schema); len(constraints) > 0 {
+		paragraph("Constraints: " + strings.Join(constraints, ", ") + ".")
+	}
+
+	if schema.Deprecated {
+		paragraph(fmt.Sprintf("Deprecated: The %s is deprecated in the specification.", kind))
+	}
+
+	return doc
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^#+\s+(.*)$`)
+	markdownList    = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	markdownNumber  = regexp.MustCompile(`^([0-9]+)[.)]\s+(.*)$`)
+)
+
+// DescriptionDoc renders the markdown _description_ as godoc comment lines.
+//
+// Paragraphs and line breaks are preserved. Headings are rendered as godoc headings, list
+// items as godoc lists and fenced code blocks as indented code blocks. Other markdown such
+// as emphasis and links are kept as is.
+func DescriptionDoc(description string) []string {
+	doc := []string{}
+
+	blank := func() {
+		if len(doc) > 0 && doc[len(doc)-1] != "" {
+			doc = append(doc, "")
+		}
+	}
+
+	code := false
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(description), "\r\n", "\n"), "\n")
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			code = !code
+			blank()
+
+			continue
+		}
+
+		if code {
+			if trimmed == "" {
+				doc = append(doc, "")
+			} else {
+				doc = append(doc, "\t"+line)
+			}
+
+			continue
+		}
+
+		if match := markdownHeading.FindStringSubmatch(trimmed); match != nil {
+			blank()
+			doc = append(doc, "# "+match[1], "")
+
+			continue
+		}
+
+		if match := markdownList.FindStringSubmatch(trimmed); match != nil {
+			doc = append(doc, "  - "+match[1])
+			continue
+		}
+
+		if match := markdownNumber.FindStringSubmatch(trimmed); match != nil {
+			doc = append(doc, "  "+match[1]+". "+match[2])
+			continue
+		}
+
+		if trimmed == "" {
+			blank()
+			continue
+		}
+
+		doc = append(doc, trimmed)
+	}
+
+	for len(doc) > 0 && doc[len(doc)-1] == "" {
+		doc = doc[:len(doc)-1]
+	}
+
+	return doc
+}
+
+// SchemaConstraints returns the constraints of _schema_ in the words of the specification,
+// e.g. `maxLength: 32`.
+func SchemaConstraints(schema *openapi3.Schema) []string {
+	constraints := []string{}
+
+	add := func(format string, args ...any) {
+		constraints = append(constraints, fmt.Sprintf(format, args...))
+	}
+
+	if schema.MinLength > 0 {
+		add("minLength: %d", schema.MinLength)
+	}
+
+	if schema.MaxLength != nil {
+		add("maxLength: %d", *schema.MaxLength)
+	}
+
+	for _, pattern := range SchemaPatterns(schema) {
+		add("pattern: %s", pattern)
+	}
+
+	if schema.Min != nil {
+		if schema.ExclusiveMin {
+			add("exclusiveMinimum: %s", formatFloat(*schema.Min))
+		} else {
+			add("minimum: %s", formatFloat(*schema.Min))
+		}
+	}
+
+	if schema.Max != nil {
+		if schema.ExclusiveMax {
+			add("exclusiveMaximum: %s", formatFloat(*schema.Max))
+		} else {
+			add("maximum: %s", formatFloat(*schema.Max))
+		}
+	}
+
+	if schema.MultipleOf != nil {
+		add("multipleOf: %s", formatFloat(*schema.MultipleOf))
+	}
+
+	if schema.MinItems > 0 {
+		add("minItems: %d", schema.MinItems)
+	}
+
+	if schema.MaxItems != nil {
+		add("maxItems: %d", *schema.MaxItems)
+	}
+
+	if schema.UniqueItems {
+		add("uniqueItems")
+	}
+
+	if schema.MinProps > 0 {
+		add("minProperties: %d", schema.MinProps)
+	}
+
+	if schema.MaxProps != nil {
+		add("maxProperties: %d", *schema.MaxProps)
+	}
+
+	return constraints
+}
@@ -0,0 +1,538 @@
+// Package fake produces random, but valid, json values of the components in a generated
+// specification. The values honors the constraints of the schemas such as enums, patterns,
+// lengths, ranges, required properties, discriminators and array bounds.
+//
+// A `Faker` is seeded and hence produces the same values for the same seed and
+// specification. The values may be unmarshalled into the generated go types using `Fill`.
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+const (
+	// DefaultMaxDepth is the default depth of nested objects and arrays where optional
+	// properties and items are no longer produced.
+	DefaultMaxDepth = 4
+	// DefaultMaxItems is the default number of items of arrays and maps, unless constrained
+	// by the schema.
+	DefaultMaxItems = 3
+	// attempts is the number of times a value is produced before giving up when it does not
+	// validate against the schema, e.g. because of a _not_ or a _oneOf_.
+	attempts = 20
+	// maxRequiredDepth is the depth where required properties are considered to recurse
+	// infinitely.
+	maxRequiredDepth = 64
+)
+
+// Faker produces random values of the types in a specification.
+type Faker struct {
+	ctx       *generator.GeneratorContext
+	rand      *rand.Rand
+	max_depth int
+	max_items int
+}
+
+// New creates a `Faker` of the types in _ctx_, as processed by `Generator.Generate`, that is
+// seeded with _seed_.
+func New(ctx *generator.GeneratorContext, seed int64) *Faker {
+	return &Faker{
+		ctx:       ctx,
+		rand:      rand.New(rand.NewSource(seed)),
+		max_depth: DefaultMaxDepth,
+		max_items: DefaultMaxItems,
+	}
+}
+
+// Factory returns a function that creates a `Faker` seeded with _seed_, e.g. for the fake
+// values in the round-trip tests, see `generator.Settings.UseFakeValues`.
+func Factory(seed int64) func(ctx *generator.GeneratorContext) generator.ValueFaker {
+	return func(ctx *generator.GeneratorContext) generator.ValueFaker {
+		return New(ctx, seed)
+	}
+}
+
+// UseMaxDepth sets the depth of nested objects and arrays where optional properties and
+// optional items are no longer produced. This limits the size of recursive types.
+func (f *Faker) UseMaxDepth(depth int) *Faker {
+	f.max_depth = depth
+	return f
+}
+
+// UseMaxItems sets the maximum number of items in arrays and maps, unless the schema
+// requires more.
+func (f *Faker) UseMaxItems(items int) *Faker {
+	f.max_items = items
+	return f
+}
+
+// Component produces a value of the component _name_ in the specification, e.g. _Pet_.
+func (f *Faker) Component(name string) (any, error) {
+	component, ok := f.ctx.GetSpecification().Components[name]
+	if !ok {
+		return nil, fmt.Errorf("component: %s not found", name)
+	}
+
+	td := f.ctx.ResolveDefinition(component)
+	if td == nil {
+		return nil, fmt.Errorf("could not resolve component: %s", name)
+	}
+
+	return f.Value(td)
+}
+
+// Names returns the sorted names of the components in the specification of _ctx_.
+func Names(ctx *generator.GeneratorContext) []string {
+	names := make([]string, 0, len(ctx.GetSpecification().Components))
+	for name := range ctx.GetSpecification().Components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Fill produces a value of _td_ and unmarshals it into _v_, e.g. a pointer to the generated
+// go type of _td_.
+func (f *Faker) Fill(td *gentypes.TypeDefinition, v any) error {
+	value, err := f.Value(td)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Value produces a json value, as unmarshalled into `any`, of _td_ that validates against
+// the resolved schema of _td_, see `generator.ResolvedSchema`.
+func (f *Faker) Value(td *gentypes.TypeDefinition) (any, error) {
+	return f.value(td, 0)
+}
+
+func (f *Faker) value(td *gentypes.TypeDefinition, depth int) (any, error) {
+	if depth > maxRequiredDepth {
+		return nil, fmt.Errorf("required properties are too deeply nested: %s", td.ID.String())
+	}
+
+	schema := generator.ResolvedSchema(f.ctx, td)
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var value any
+		if value, err = f.produce(td, schema, depth); err != nil {
+			return nil, err
+		}
+
+		if len(td.DiscriminatorComponents) > 0 {
+			// Validated against the discriminated component
+			return value, nil
+		}
+
+		if err = schema.VisitJSON(value); err == nil {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not produce a valid value of: %s: %s", td.ID.String(), err.Error())
+}
+
+func (f *Faker) produce(td *gentypes.TypeDefinition, schema *openapi3.Schema, depth int) (any, error) {
+	switch {
+	case len(schema.Enum) > 0:
+		return schema.Enum[f.rand.Intn(len(schema.Enum))], nil
+	case len(td.DiscriminatorComponents) > 0:
+		return f.discriminated(td, depth)
+	case len(td.OneOf) > 0:
+		return f.component(&td.OneOf[f.rand.Intn(len(td.OneOf))], depth)
+	case len(td.AnyOf) > 0:
+		return f.component(&td.AnyOf[f.rand.Intn(len(td.AnyOf))], depth)
+	case td.IsArray():
+		return f.array(td, schema, depth)
+	case td.IsObject(), td.IsMap():
+		return f.object(td, schema, depth)
+	}
+
+	switch schema.Type {
+	case "string":
+		return f.String(schema)
+	case "integer":
+		return f.Integer(schema), nil
+	case "number":
+		return f.Number(schema), nil
+	case "boolean":
+		return f.rand.Intn(2) == 0, nil
+	}
+
+	// Any value
+	return f.letters(1 + f.rand.Intn(8)), nil
+}
+
+func (f *Faker) component(component *gentypes.ComponentDefinition, depth int) (any, error) {
+	td := f.ctx.ResolveDefinition(component)
+	if td == nil {
+		return nil, fmt.Errorf("could not resolve: %s", component.ID.String())
+	}
+
+	return f.value(td, depth)
+}
+
+// discriminated produces a value of one of the discriminated components and sets the
+// discriminator property to its mapping. The value is validated against the schema of the
+// component since the discriminator selects it.
+func (f *Faker) discriminated(td *gentypes.TypeDefinition, depth int) (any, error) {
+	dc := td.DiscriminatorComponents[f.rand.Intn(len(td.DiscriminatorComponents))]
+
+	member := f.ctx.ResolveDefinition(&dc.ComponentDefinition)
+	if member == nil {
+		return nil, fmt.Errorf("could not resolve: %s", dc.ID.String())
+	}
+
+	mapping := dc.MapFrom
+	if mapping == "" {
+		mapping = dc.ID.TypeName
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var value any
+		if value, err = f.value(member, depth); err != nil {
+			return nil, err
+		}
+
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("discriminated component is not an object: %s", dc.ID.String())
+		}
+
+		object[dc.Discriminator] = mapping
+
+		if err = generator.ResolvedSchema(f.ctx, member).VisitJSON(object); err == nil {
+			return object, nil
+		}
+	}
+
+	return nil, fmt.Errorf("discriminator: %s=%s is not valid for: %s: %s", dc.Discriminator, mapping, dc.ID.String(), err.Error())
+}
+
+func (f *Faker) array(td *gentypes.TypeDefinition, schema *openapi3.Schema, depth int) (any, error) {
+	items := []any{}
+	seen := map[string]bool{}
+
+	count := f.count(int(schema.MinItems), schema.MaxItems, depth)
+	for tries := 0; len(items) < count && tries < count*attempts; tries++ {
+		item, err := f.component(td.Items, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if schema.UniqueItems {
+			data, _ := json.Marshal(item)
+			if seen[string(data)] {
+				continue
+			}
+
+			seen[string(data)] = true
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (f *Faker) object(td *gentypes.TypeDefinition, schema *openapi3.Schema, depth int) (any, error) {
+	object := map[string]any{}
+	optional := []*gentypes.Property{}
+
+	for _, property := range generator.AllProperties(f.ctx, td) {
+		if !property.Required && !generator.ContainsString(schema.Required, property.PropertyName) {
+			optional = append(optional, property)
+			continue
+		}
+
+		value, err := f.component(&property.ComponentDefinition, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		object[property.PropertyName] = value
+	}
+
+	min_properties := int(schema.MinProps)
+
+	for _, property := range optional {
+		if len(object) >= min_properties && (depth >= f.max_depth || f.rand.Intn(2) == 0) {
+			continue
+		}
+
+		if schema.MaxProps != nil && uint64(len(object)) >= *schema.MaxProps {
+			break
+		}
+
+		value, err := f.component(&property.ComponentDefinition, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		object[property.PropertyName] = value
+	}
+
+	if td.AdditionalProperties == nil {
+		return object, nil
+	}
+
+	var max_properties *uint64
+	if schema.MaxProps != nil {
+		remaining := uint64(0)
+		if *schema.MaxProps > uint64(len(object)) {
+			remaining = *schema.MaxProps - uint64(len(object))
+		}
+
+		max_properties = &remaining
+	}
+
+	min_additional := 0
+	if min_properties > len(object) {
+		min_additional = min_properties - len(object)
+	}
+
+	count := f.count(min_additional, max_properties, depth)
+	for added := 0; added < count; {
+		name := f.letters(5 + f.rand.Intn(4))
+		if _, ok := object[name]; ok {
+			continue
+		}
+
+		value, err := f.component(td.AdditionalProperties, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		object[name] = value
+		added++
+	}
+
+	return object, nil
+}
+
+// count returns the number of items between _min_ and _max_. When deeper than the max
+// depth, the minimum is returned.
+func (f *Faker) count(min int, max *uint64, depth int) int {
+	upper := min + f.max_items
+	if max != nil && int(*max) < upper {
+		upper = int(*max)
+	}
+
+	if depth >= f.max_depth || upper <= min {
+		return min
+	}
+
+	return min + f.rand.Intn(upper-min+1)
+}
+
+// String produces a string that honors the _format_, _pattern_ and lengths of _schema_.
+func (f *Faker) String(schema *openapi3.Schema) (string, error) {
+	switch schema.Format {
+	case "date-time":
+		return f.time().Format(time.RFC3339), nil
+	case "date":
+		return f.time().Format("2006-01-02"), nil
+	case "time":
+		return f.time().Format("15:04:05"), nil
+	case "uuid":
+		b := make([]byte, 16)
+		f.rand.Read(b)
+		b[6], b[8] = (b[6]&0x0f)|0x40, (b[8]&0x3f)|0x80
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+	case "email":
+		return f.letters(3+f.rand.Intn(6)) + "@example.com", nil
+	case "uri", "url":
+		return "https://example.com/" + f.letters(3+f.rand.Intn(6)), nil
+	case "hostname":
+		return f.letters(3+f.rand.Intn(6)) + ".example.com", nil
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", 1+f.rand.Intn(254), f.rand.Intn(256), f.rand.Intn(256), 1+f.rand.Intn(254)), nil
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x:%x", f.rand.Intn(0x10000), f.rand.Intn(0x10000)), nil
+	case "byte":
+		min, max := f.length(schema)
+		// The base64 encoding is 4/3 of the length
+		b := make([]byte, 3*((min+max)/2)/4)
+		f.rand.Read(b)
+
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+
+	if patterns := generator.SchemaPatterns(schema); len(patterns) > 0 {
+		return f.patterns(patterns)
+	}
+
+	min, max := f.length(schema)
+	return f.letters(min + f.rand.Intn(max-min+1)), nil
+}
+
+// patterns produces a string from the first of the _patterns_ that also matches the rest.
+func (f *Faker) patterns(patterns []string) (string, error) {
+	others := []*regexp.Regexp{}
+	for _, pattern := range patterns[1:] {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern: %s: %s", pattern, err.Error())
+		}
+
+		others = append(others, re)
+	}
+
+	for i := 0; i < attempts; i++ {
+		value, err := f.Pattern(patterns[0])
+		if err != nil {
+			return "", err
+		}
+
+		matches := true
+		for _, re := range others {
+			matches = matches && re.MatchString(value)
+		}
+
+		if matches {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("no value matches all patterns: %s", strings.Join(patterns, ", "))
+}
+
+// length returns the minimum and maximum length of a string.
+func (f *Faker) length(schema *openapi3.Schema) (int, int) {
+	min := int(schema.MinLength)
+
+	max := min + 12
+	if schema.MaxLength != nil {
+		max = int(*schema.MaxLength)
+	}
+
+	if min == 0 && max > 0 {
+		min = 1
+	}
+
+	return min, max
+}
+
+// Integer produces an integer within the range, and of the _multipleOf_, of _schema_.
+func (f *Faker) Integer(schema *openapi3.Schema) int64 {
+	lo, hi := f.bounds(schema)
+
+	min, max := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if schema.ExclusiveMin && float64(min) == lo {
+		min++
+	}
+
+	if schema.ExclusiveMax && float64(max) == hi {
+		max--
+	}
+
+	if schema.MultipleOf != nil && *schema.MultipleOf >= 1 {
+		multiple := int64(*schema.MultipleOf)
+		first, last := ceilDiv(min, multiple), floorDiv(max, multiple)
+
+		if last < first {
+			return first * multiple
+		}
+
+		return (first + f.rand.Int63n(last-first+1)) * multiple
+	}
+
+	if max < min {
+		return min
+	}
+
+	return min + f.rand.Int63n(max-min+1)
+}
+
+// Number produces a number within the range, and of the _multipleOf_, of _schema_.
+func (f *Faker) Number(schema *openapi3.Schema) float64 {
+	lo, hi := f.bounds(schema)
+
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		multiple := *schema.MultipleOf
+		first, last := math.Ceil(lo/multiple), math.Floor(hi/multiple)
+
+		if last < first {
+			return first * multiple
+		}
+
+		return (first + float64(f.rand.Int63n(int64(last-first)+1))) * multiple
+	}
+
+	value := lo + f.rand.Float64()*(hi-lo)
+
+	// Two decimals are enough and keeps the json short, unless outside of a narrow range
+	if rounded := math.Round(value*100) / 100; rounded >= lo && rounded <= hi &&
+		!(schema.ExclusiveMin && rounded == lo) && !(schema.ExclusiveMax && rounded == hi) {
+		return rounded
+	}
+
+	return value
+}
+
+// bounds returns the range of a number where an open end is 1000 from the other end.
+func (f *Faker) bounds(schema *openapi3.Schema) (float64, float64) {
+	switch {
+	case schema.Min != nil && schema.Max != nil:
+		return *schema.Min, *schema.Max
+	case schema.Min != nil:
+		return *schema.Min, *schema.Min + 1000
+	case schema.Max != nil:
+		return math.Min(0, *schema.Max-1000), *schema.Max
+	}
+
+	return 0, 1000
+}
+
+// time returns a time between year 2000 and 2030 in whole seconds.
+func (f *Faker) time() time.Time {
+	return time.Unix(946684800+f.rand.Int63n(946684800), 0).UTC()
+}
+
+const alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// letters returns _n_ random lower case letters.
+func (f *Faker) letters(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte(alphabet[f.rand.Intn(len(alphabet))])
+	}
+
+	return sb.String()
+}
+
+func ceilDiv(a, b int64) int64 {
+	return -floorDiv(-a, b)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
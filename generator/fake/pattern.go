@@ -0,0 +1,110 @@
+package fake
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// maxRepeat is the maximum number of repetitions of unbounded repeats e.g. `*` and `+`.
+const maxRepeat = 8
+
+// Pattern returns a random string that matches the regular expression _pattern_.
+//
+// The pattern is not anchored, as in the _pattern_ keyword, but the generated string only
+// consists of what the pattern matches. Only printable ASCII is generated for `.` and negated
+// character classes.
+func (f *Faker) Pattern(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %s: %s", pattern, err.Error())
+	}
+
+	var sb strings.Builder
+	if err := f.pattern(&sb, re.Simplify()); err != nil {
+		return "", fmt.Errorf("pattern: %s: %s", pattern, err.Error())
+	}
+
+	return sb.String(), nil
+}
+
+func (f *Faker) pattern(sb *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && f.rand.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(f.charClass(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		sb.WriteRune(rune(' ' + f.rand.Intn('~'-' '+1)))
+	case syntax.OpCapture:
+		return f.pattern(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := f.pattern(sb, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		return f.pattern(sb, re.Sub[f.rand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, maxRepeat
+		case syntax.OpPlus:
+			min, max = 1, maxRepeat
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+
+		if max < 0 {
+			max = min + maxRepeat
+		}
+
+		for i := min + f.rand.Intn(max-min+1); i > 0; i-- {
+			if err := f.pattern(sb, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported regular expression: %s", re.String())
+	}
+
+	return nil
+}
+
+// charClass returns a random rune from the ranges of a character class. Large ranges, such
+// as of a negated class, are limited to printable ASCII when possible.
+func (f *Faker) charClass(ranges []rune) rune {
+	printable := []rune{}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+
+		if hi > '~' {
+			hi = '~'
+		}
+
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	i := 2 * f.rand.Intn(len(ranges)/2)
+	return ranges[i] + rune(f.rand.Intn(int(ranges[i+1]-ranges[i])+1))
+}
@@ -3,26 +3,45 @@ package generator
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"gopkg.in/yaml.v3"
 )
 
 // GeneratorContext is used when generating the types.
 type GeneratorContext struct {
 	settings      Settings
-	resolver      gentypes.ReferenceResolverImpl
+	resolver      gentypes.ReferenceResolver
 	specification gentypes.OpenAPISpecificationDefinition
+	files         []GeneratedFile
+	cycles        []Cycle
+	diagnostics   []Diagnostic
+	// sources are the parsed source files used to locate diagnostics, keyed on path.
+	sources map[string]*yaml.Node
 }
 
 func (ctx *GeneratorContext) GetSpecification() *gentypes.OpenAPISpecificationDefinition {
 	return &ctx.specification
 }
 
-func (ctx *GeneratorContext) GetResolver() *gentypes.ReferenceResolverImpl {
-	return &ctx.resolver
+// GetFiles returns the files rendered by the last `Generator.Generate`.
+func (ctx *GeneratorContext) GetFiles() []GeneratedFile {
+	return ctx.files
+}
+
+// GetCycles returns the reference cycles detected by the last `Generator.Generate`.
+func (ctx *GeneratorContext) GetCycles() []Cycle {
+	return ctx.cycles
+}
+
+// GetResolver returns the resolver of the components, see `Settings.UseReferenceResolver`.
+func (ctx *GeneratorContext) GetResolver() gentypes.ReferenceResolver {
+	return ctx.resolver
 }
 
 func (ctx *GeneratorContext) ResolveTypeDefinition(ref *gentypes.ComponentReference) *gentypes.TypeDefinition {
@@ -44,6 +63,24 @@ func (ctx *GeneratorContext) ResolveTypeDefinition(ref *gentypes.ComponentRefere
 	}
 }
 
+// ResolveDefinition returns the definition of the _component_, either directly or
+// by resolving its reference.
+func (ctx *GeneratorContext) ResolveDefinition(component *gentypes.ComponentDefinition) *gentypes.TypeDefinition {
+	if component == nil {
+		return nil
+	}
+
+	if component.Definition != nil {
+		return component.Definition
+	}
+
+	if component.Reference == nil {
+		return nil
+	}
+
+	return ctx.ResolveTypeDefinition(component.Reference)
+}
+
 type Generator struct {
 	// settings is only used to clone into `GeneratorContext`
 	settings Settings
@@ -58,19 +95,38 @@ func NewGenerator(settings Settings) *Generator {
 	}
 
 	if gen.settings.loader == nil {
-		// Default loader
+		// Default loader without the global cache of kin-openapi, since the models and the
+		// generated specification file may change between generations in the same process
 		gen.settings.loader = &openapi3.Loader{
 			Context:               context.Background(),
 			IsExternalRefsAllowed: true,
+			ReadFromURIFunc: openapi3.ReadFromURIs(
+				openapi3.ReadFromHTTP(http.DefaultClient), openapi3.ReadFromFile,
+			),
 		}
 	}
 
+	if gen.settings.type_mapping == nil {
+		gen.settings.type_mapping = DefaultTypeMapping()
+	} else {
+		// Make sure later changes to the settings do not affect this generator
+		gen.settings.type_mapping = gen.settings.type_mapping.Clone()
+	}
+
 	return gen
 }
 
 func (gen *Generator) Generate(ctx *GeneratorContext) error {
 	ctx.settings = gen.settings
-	ctx.resolver = *gentypes.NewReferenceResolver()
+	ctx.resolver = gen.settings.resolver
+	if ctx.resolver == nil {
+		ctx.resolver = gentypes.NewReferenceResolver()
+	}
+
+	ctx.files = nil
+	ctx.cycles = nil
+	ctx.diagnostics = nil
+	ctx.sources = nil
 
 	ctx.specification = gentypes.OpenAPISpecificationDefinition{
 		Components: map[string]*gentypes.ComponentDefinition{},
@@ -91,26 +147,70 @@ func (gen *Generator) Generate(ctx *GeneratorContext) error {
 		return err
 	}
 
-	// Load the specification
-	doc, err := ctx.settings.loader.LoadFromFile(ctx.settings.spec)
+	// Load the specification, the models are loaded by the model loader to allow recursive references
+	doc, err := NewModelLoader(ctx.settings.loader).LoadSpecification(ctx.settings.spec)
+	if err != nil {
+		return err
+	}
+
+	// The examples and the defaults are checked by CheckExamples and reported as diagnostics
+	restore := StripDefaults(doc.Components.Schemas)
+	err = doc.Validate(ctx.settings.loader.Context, openapi3.DisableExamplesValidation())
+	restore()
 
 	if err != nil {
 		return err
 	}
 
-	err = doc.Validate(ctx.settings.loader.Context)
+	if err = ProcessSpecification(ctx, doc.Components.Schemas); err != nil {
+		return err
+	}
+
+	if ctx.cycles, err = DetectCycles(ctx); err != nil {
+		return err
+	}
+
+	if err = CheckCompositions(ctx); err != nil {
+		return err
+	}
+
+	if err = CheckExamples(ctx); err != nil {
+		return err
+	}
+
+	if ctx.settings.output == "" {
+		return nil
+	}
+
+	if ctx.files, err = Render(ctx); err != nil {
+		return err
+	}
+
+	plugged, err := RunPlugins(ctx, ctx.files)
 	if err != nil {
 		return err
 	}
 
-	ProcessSpecification(ctx, doc.Components.Schemas)
+	if len(plugged) > 0 {
+		ctx.files = append(ctx.files, plugged...)
+		sortFiles(ctx.files)
+	}
 
-	return nil
+	return WriteFiles(ctx.settings.output, ctx.files)
 }
 
 func ProcessSpecification(ctx *GeneratorContext, schemas map[string]*openapi3.SchemaRef) error {
-	// Iterate all components and create them
-	for componentName, v := range schemas {
+	names := make([]string, 0, len(schemas))
+	for componentName := range schemas {
+		names = append(names, componentName)
+	}
+
+	// Create the components in name order such that the diagnostics are stable
+	sort.Strings(names)
+
+	for _, componentName := range names {
+		v := schemas[componentName]
+
 		// Is it our synthetic component?
 		if componentName == "PackageInfo" &&
 			v.Value != nil &&
@@ -130,12 +230,17 @@ func ProcessSpecification(ctx *GeneratorContext, schemas map[string]*openapi3.Sc
 			return err
 		}
 
+		if comp == nil {
+			// Already created when referenced by another component
+			comp = ctx.resolver.ResolveComponent(id)
+		}
+
 		if comp != nil {
 			ctx.specification.Components[componentName] = comp
 		}
 	}
 
-	return nil
+	return VisitComponents(ctx)
 }
 
 // CreateComponentFromReference will create the `ComponentDefinition` and return it. If there are sub-components such
@@ -157,9 +262,14 @@ func CreateComponentFromReference(
 		)
 	}
 	// This is a reference
+	reference, err := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, ref)
+	if err != nil {
+		return nil, err
+	}
+
 	component := &gentypes.ComponentDefinition{
 		ID:         *componentId,
-		Reference:  ResolveReferenceAndSwitchIfNeeded(ctx, componentId, ref),
+		Reference:  reference,
 		Definition: nil,
 	}
 
@@ -169,7 +279,37 @@ func CreateComponentFromReference(
 		return component, nil
 	}
 
-	CreateComponentFromDefinition(ctx, component.Reference, ref.Value)
+	if _, err := CreateComponentFromDefinition(ctx, component.Reference, ref.Value); err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// CreateInlineComponent creates the component of a schema that is declared inline in another schema, such
+// as array items, and registers it as _componentId_. If already registered, the registered component is returned.
+//
+// If _ref_ is a reference, a reference component is returned. Otherwise the definition is created and marked
+// as `TypeDefinition.Inline`.
+func CreateInlineComponent(
+	ctx *GeneratorContext,
+	componentId *gentypes.ComponentReference,
+	ref *openapi3.SchemaRef) (*gentypes.ComponentDefinition, error) {
+
+	if component := ctx.resolver.ResolveComponent(componentId); component != nil {
+		return component, nil
+	}
+
+	if IsReference(ref) {
+		return CreateComponentFromReference(ctx, componentId, ref)
+	}
+
+	component, err := CreateComponentFromDefinition(ctx, componentId, ref.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	component.Definition.Inline = true
 	return component, nil
 }
 
@@ -182,12 +322,18 @@ func CreateComponentFromDefinition(
 		return nil, nil
 	}
 
+	goType, err := ResolveGoType(ctx, componentId, def)
+	if err != nil {
+		return nil, err
+	}
+
 	td := gentypes.TypeDefinition{
 		ID:          *componentId,
 		GoPackage:   ResolveGoPackage(ctx, componentId),
 		Schema:      def,
 		Composition: []gentypes.Composition{},
 		Properties:  []gentypes.Property{},
+		GoType:      goType,
 	}
 
 	component := &gentypes.ComponentDefinition{
@@ -204,17 +350,33 @@ func CreateComponentFromDefinition(
 		return nil, err
 	}
 
+	// Unions without discriminator
+	if err := HandleUnion(ctx, &td, component, td.Schema); err != nil {
+		return nil, err
+	}
+
 	// Composition
 	if err := HandleComposition(ctx, &td, component, def); err != nil {
 		return nil, err
 	}
 
-	// Handle Reference Properties
-	if err := HandleProperties(ctx, &td, component, def); err != nil {
+	// Handle Reference Properties (the schema may have been merged by composition)
+	if err := HandleProperties(ctx, &td, component, td.Schema); err != nil {
+		return nil, err
+	}
+
+	// Arrays and maps
+	if err := HandleArrayDefinition(ctx, &td, component, td.Schema); err != nil {
 		return nil, err
 	}
 
-	// TODO: Chase down anyOf, oneOf
+	if err := HandleAdditionalProperties(ctx, &td, component, td.Schema); err != nil {
+		return nil, err
+	}
+
+	if err := HandleNot(ctx, &td, component, td.Schema); err != nil {
+		return nil, err
+	}
 
 	return component, nil
 }
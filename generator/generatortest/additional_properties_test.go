@@ -0,0 +1,61 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdditionalProperties(t *testing.T) {
+	_, files := generate(t, "maps", nil, ".:maps/*.yaml")
+
+	source := files["maps/maps.go"]
+
+	assert.Contains(t, source, "type Labels map[string]string")
+	assert.Contains(t, source, "type Metadata map[string]any")
+	assert.Contains(t, source, "AdditionalProperties map[string]Measurement `json:\"-\"`")
+	assert.Contains(t, source, "Labels Labels `json:\"labels,omitempty\"`")
+
+	goTest(t, "maps", "maps", "roundtrip_test.go", `package maps
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"id":"d1","labels":{"room":"kitchen"},"temperature":{"value":21.5}}`+"`"+`
+
+	var device Device
+	if err := json.Unmarshal([]byte(input), &device); err != nil {
+		t.Fatal(err)
+	}
+
+	if device.ID != "d1" || device.Labels["room"] != "kitchen" {
+		t.Fatalf("declared properties not unmarshalled: %+v", device)
+	}
+
+	if device.AdditionalProperties["temperature"].Value != 21.5 {
+		t.Fatalf("additional properties not unmarshalled: %+v", device)
+	}
+
+	if err := device.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != input {
+		t.Fatalf("expected %s got %s", input, string(data))
+	}
+
+	device.AdditionalProperties["humidity"] = Measurement{Value: -1}
+	if err := device.Validate(); err == nil {
+		t.Fatal("expected validation of additional properties to fail")
+	}
+}
+`)
+}
@@ -0,0 +1,64 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestedArraysAndUnions(t *testing.T) {
+	_, files := generate(t, "arrays", nil, ".:arrays/*.yaml")
+
+	source := files["arrays/arrays.go"]
+
+	assert.Contains(t, source, "Cells  [][]float64    `json:\"cells\"`")
+	assert.Contains(t, source, "type Matrix [][]int")
+	assert.Contains(t, source, "Values []Value        `json:\"values,omitempty\"`")
+	assert.Contains(t, source, "type Value struct")
+	assert.Contains(t, source, "Text  *string")
+	assert.Contains(t, source, "Count *int")
+
+	goTest(t, "arrays", "arrays", "roundtrip_test.go", `package arrays
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"cells":[[1,2],[3]],"tags":["red"],"values":["abc",42]}`+"`"+`
+
+	var grid Grid
+	if err := json.Unmarshal([]byte(input), &grid); err != nil {
+		t.Fatal(err)
+	}
+
+	if grid.Values[0].Text == nil || grid.Values[1].Count == nil {
+		t.Fatalf("union values not unmarshalled: %+v", grid.Values)
+	}
+
+	if err := grid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != input {
+		t.Fatalf("expected %s got %s", input, string(data))
+	}
+
+	var value Value
+	if err := json.Unmarshal([]byte(`+"`"+`"too long text"`+"`"+`), &value); err == nil {
+		t.Fatal("expected a value matching no type to fail")
+	}
+
+	grid.Cells[0][0] = -1
+	if err := grid.Validate(); err == nil {
+		t.Fatal("expected validation of nested array items to fail")
+	}
+}
+`)
+}
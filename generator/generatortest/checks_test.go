@@ -0,0 +1,41 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExamples(t *testing.T) {
+	ctx, files := generate(t, "checks", nil, ".:checks/*.yaml")
+
+	diagnostics := []string{}
+	for _, diagnostic := range ctx.GetDiagnostics() {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	assert.Equal(t, []string{
+		"checks/checks.yaml:35:3: checks/checks#/Item: example does not match the schema: /code: maximum string length is 4",
+		"checks/checks.yaml:44:7: checks/checks#/Item: examples/missing/value does not match the schema: /name: property \"name\" is missing",
+		// The maxLength is from the Base composition
+		"checks/checks.yaml:15:11: checks/checks#/Item: properties/code/example does not match the schema: maximum string length is 4",
+		"checks/checks.yaml:16:11: checks/checks#/Item: properties/code/default does not match the schema: maximum string length is 4",
+		"checks/checks.yaml:28:7: checks/checks#/Item: properties/count/example does not match the schema: number must be at least 1",
+		"checks/checks.yaml:24:7: checks/checks#/Item: properties/name/example does not match the schema: maximum string length is 8",
+		"checks/checks.yaml:34:9: checks/checks#/Item: properties/tags/items/example does not match the schema: value \"blue\" is not one of the allowed values",
+		// Defaults that do not match their own schema do not fail the validation when loaded
+		"checks/checks.yaml:50:3: checks/checks#/Label: default does not match the schema: maximum string length is 2",
+		"checks/checks.yaml:60:7: checks/checks#/Tagged: properties/level/default does not match the schema: number must be at least 1",
+	}, diagnostics)
+
+	assert.Equal(t, &generator.Location{
+		File:    "checks/checks.yaml",
+		Pointer: "/Item/properties/name/example",
+		Line:    24,
+		Column:  7,
+	}, ctx.GetDiagnostics()[5].Location)
+
+	// The invalid defaults are not applied
+	assert.Contains(t, files["checks/checks.go"], "func (v *Tagged) ApplyDefaults() {\n}")
+}
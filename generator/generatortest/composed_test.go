@@ -0,0 +1,51 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineComposedProperties(t *testing.T) {
+	_, files := generate(t, "composed", nil, ".:composed/*.yaml")
+
+	source := files["composed/composed.go"]
+
+	assert.Contains(t, source, "type OrderPayment struct")
+	assert.Contains(t, source, "type OrderContact struct")
+	assert.Contains(t, source, "type OrderShipping struct")
+	assert.Contains(t, source, "type OrderPaymentCard struct")
+
+	goTest(t, "composed", "composed", "roundtrip_test.go", `package composed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"contact":42,"payment":{"number":"4111"},"shipping":{"street":"Main","instructions":"Ring twice"}}`+"`"+`
+
+	var order Order
+	if err := json.Unmarshal([]byte(input), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	if order.Payment.Card == nil || order.Payment.Card.Number != "4111" {
+		t.Fatalf("payment not unmarshalled: %+v", order.Payment)
+	}
+
+	if order.Contact.Phone == nil || *order.Contact.Phone != 42 {
+		t.Fatalf("contact not unmarshalled: %+v", order.Contact)
+	}
+
+	if order.Shipping.Street != "Main" || order.Shipping.Instructions == nil {
+		t.Fatalf("shipping not unmarshalled: %+v", order.Shipping)
+	}
+
+	if err := order.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+`)
+}
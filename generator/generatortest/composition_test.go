@@ -0,0 +1,71 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllOfComposition(t *testing.T) {
+	_, files := generate(t, "composition", nil, ".:composition/*.yaml")
+
+	source := files["composition/composition.go"]
+
+	assert.Contains(t, source, "type Pet struct {\n\tBase\n\tCreated *time.Time `json:\"created,omitempty\"`\n\tName    string     `json:\"name\"`\n}")
+	assert.Contains(t, source, "type Flat struct {\n\t// Constraints: minLength: 1.\n\tID      string     `json:\"id\"`\n\tCreated *time.Time `json:\"created,omitempty\"`\n}")
+
+	goTest(t, "composition", "composition", "roundtrip_test.go", `package composition
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"created":"2022-12-24T10:00:00Z","id":"p1","name":"Fido"}`+"`"+`
+
+	var pet Pet
+	if err := json.Unmarshal([]byte(input), &pet); err != nil {
+		t.Fatal(err)
+	}
+
+	if pet.ID != "p1" || pet.Name != "Fido" || pet.Created == nil {
+		t.Fatalf("not unmarshalled: %+v", pet)
+	}
+
+	if err := pet.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != input {
+		t.Fatalf("expected %s got %s", input, string(data))
+	}
+
+	pet.ID = ""
+	if err := pet.Validate(); err == nil {
+		t.Fatal("expected validation of the embedded type to fail")
+	}
+}
+`)
+}
+
+func TestAllOfCompositionWithConflictingPropertyTypesShallFail(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	settings := generator.NewSettings(generator.Templates{}).
+		UseModelPath(filepath.Join(cwd, "testdata"), outputPackage+"/conflict").
+		Include(".:conflict/*.yaml")
+
+	err := settings.ToGenerator().Generate(&generator.GeneratorContext{})
+
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "property: id in composition")
+}
@@ -0,0 +1,149 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCyclicComponents(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	ctx, files := generate(t, "cycles", func(settings *generator.Settings) {
+		settings.UseSpec(filepath.Join(cwd, "testdata", "cycles", "api.yaml"), outputPackage+"/cycles")
+	})
+
+	cycles := []string{}
+	for _, cycle := range ctx.GetCycles() {
+		cycles = append(cycles, cycle.String())
+	}
+
+	assert.Equal(t, []string{
+		"api#/components/schemas/Company -> api#/components/schemas/Person -> api#/components/schemas/Company",
+		"api#/components/schemas/Node -> api#/components/schemas/Node_Children -> api#/components/schemas/Node",
+		"api#/components/schemas/Node -> api#/components/schemas/Node",
+	}, cycles)
+
+	assert.Equal(t, "ceo", ctx.GetCycles()[0].Pointer.PropertyName)
+	assert.Nil(t, ctx.GetCycles()[1].Pointer)
+	assert.Nil(t, ctx.GetCycles()[2].Pointer)
+
+	source := files["api.go"]
+
+	assert.Contains(t, source, "Ceo  *Person `json:\"ceo\"`")
+	assert.Contains(t, source, "Employer Company `json:\"employer\"`")
+	assert.Contains(t, source, "Parent   *Node  `json:\"parent,omitempty\"`")
+
+	goTest(t, "cycles", ".", "roundtrip_test.go", `package cycles
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"children":[{"name":"leaf"}],"name":"root"}`+"`"+`
+
+	var node Node
+	if err := json.Unmarshal([]byte(input), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != input {
+		t.Fatalf("expected %s got %s", input, string(data))
+	}
+
+	if err := (Company{Name: "acme"}).Validate(); err == nil {
+		t.Fatal("expected the required ceo to be validated")
+	}
+}
+`)
+}
+
+func TestCyclesThroughVisitedComponents(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	ctx, files := generate(t, "mutual", func(settings *generator.Settings) {
+		settings.UseSpec(filepath.Join(cwd, "testdata", "cycles", "mutual.yaml"), outputPackage+"/mutual")
+	})
+
+	cycles := []string{}
+	pointers := []string{}
+	for _, cycle := range ctx.GetCycles() {
+		cycles = append(cycles, cycle.String())
+		if cycle.Pointer != nil {
+			pointers = append(pointers, cycle.Pointer.PropertyName)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"mutual#/components/schemas/A -> mutual#/components/schemas/B -> mutual#/components/schemas/A",
+		"mutual#/components/schemas/A -> mutual#/components/schemas/C -> mutual#/components/schemas/B -> mutual#/components/schemas/A",
+	}, cycles)
+
+	assert.Equal(t, []string{"b", "c"}, pointers)
+
+	source := files["mutual.go"]
+
+	assert.Contains(t, source, "B *B `json:\"b\"`")
+	assert.Contains(t, source, "C *C `json:\"c\"`")
+	assert.Contains(t, source, "A A `json:\"a\"`")
+
+	vet(t, "mutual")
+}
+
+func TestCyclesBetweenModels(t *testing.T) {
+	ctx, files := generate(t, "modelcycles", nil, ".:modelcycles/*.yaml")
+
+	cycles := []string{}
+	for _, cycle := range ctx.GetCycles() {
+		cycles = append(cycles, cycle.String())
+	}
+
+	assert.Equal(t, []string{
+		"modelcycles/company#/Company -> modelcycles/person#/Person -> modelcycles/company#/Company",
+		"modelcycles/person#/Team -> modelcycles/person#/Team",
+	}, cycles)
+
+	assert.Contains(t, files["modelcycles/company.go"], "Ceo  *Person `json:\"ceo,omitempty\"`")
+	assert.Contains(t, files["modelcycles/person.go"], "Employer Company `json:\"employer\"`")
+	assert.Contains(t, files["modelcycles/person.go"], "Parent  *Team    `json:\"parent,omitempty\"`")
+
+	goTest(t, "modelcycles", "modelcycles", "roundtrip_test.go", `package modelcycles
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	input := `+"`"+`{"members":[{"employer":{"ceo":{"employer":{"name":"acme"},"name":"jane"},"name":"acme"},"name":"joe"}],"name":"dev","parent":{"name":"all"}}`+"`"+`
+
+	var team Team
+	if err := json.Unmarshal([]byte(input), &team); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != input {
+		t.Fatalf("expected %s got %s", input, string(data))
+	}
+}
+`)
+}
@@ -0,0 +1,74 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaults(t *testing.T) {
+	_, files := generate(t, "defaults", func(settings *generator.Settings) {
+		settings.UseDefaultsOnUnmarshal(true)
+	}, ".:defaults/*.yaml")
+
+	source := files["defaults/defaults.go"]
+
+	assert.Contains(t, source, "func NewSettings(name string) Settings {")
+	assert.Contains(t, source, "func (v *Settings) ApplyDefaults() {")
+	assert.Contains(t, source, "func NewLimits() Limits {")
+
+	goTest(t, "defaults", "defaults", "defaults_test.go", `package defaults
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDefaults(t *testing.T) {
+	settings := NewSettings("main")
+
+	if settings.Name != "main" || *settings.Title != "untitled" || *settings.Retries != 3 ||
+		*settings.Ratio != 0.5 || !*settings.Enabled || len(settings.Tags) != 2 || *settings.Color != "green" {
+		t.Fatalf("expected the defaults to be applied: %+v", settings)
+	}
+
+	title := "custom"
+	explicit := Settings{Name: "other", Title: &title, Limits: &Limits{}}
+	explicit.ApplyDefaults()
+
+	if *explicit.Title != "custom" || *explicit.Limits.Max != 10 {
+		t.Fatalf("expected set values to be kept and nested defaults applied: %+v", explicit)
+	}
+
+	var unmarshalled Settings
+	if err := json.Unmarshal([]byte(`+"`"+`{"name":"json","retries":5,"limits":{}}`+"`"+`), &unmarshalled); err != nil {
+		t.Fatal(err)
+	}
+
+	if *unmarshalled.Retries != 5 || *unmarshalled.Title != "untitled" || *unmarshalled.Limits.Max != 10 {
+		t.Fatalf("expected the defaults to be applied on unmarshal: %+v", unmarshalled)
+	}
+}
+`)
+}
+
+func TestDefaultsThatCanNotBeUnmarshalledShallAddDiagnostics(t *testing.T) {
+	ctx, files := generate(t, "baddefaults", nil, ".:baddefaults/*.yaml")
+
+	diagnostics := []string{}
+	for _, diagnostic := range ctx.GetDiagnostics() {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	assert.Equal(t, []string{
+		"baddefaults/baddefaults.yaml:18:3: baddefaults/baddefaults#/ScheduleID: " +
+			"default is not a valid types.UUID, it is not applied: invalid uuid: not-a-uuid",
+		"baddefaults/baddefaults.yaml:9:7: baddefaults/baddefaults#/Schedule: " +
+			"default is not a valid time.Time, it is not applied: parsing time \"2023-02-30T10:00:00Z\": day out of range",
+	}, diagnostics)
+
+	source := files["baddefaults/baddefaults.go"]
+	assert.NotContains(t, source, "2023-02-30T10:00:00Z")
+	assert.Contains(t, source, "2023-06-01")
+}
@@ -0,0 +1,44 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocComments(t *testing.T) {
+	_, files := generate(t, "docs", nil, ".:docs/*.yaml")
+
+	source := files["docs/docs.go"]
+
+	// Markdown is rendered as godoc paragraphs, headings, lists and code blocks
+	assert.Contains(t, source, `// Order is generated from docs/docs#/Order.
+//
+// An order placed by a customer.
+//
+// # Lifecycle
+//
+// An order is _placed_, then:
+//   - shipped
+//   - delivered
+//
+// Create it using:
+//
+//	order := NewOrder("o1")
+type Order struct {`)
+
+	assert.Contains(t, source, `	// The unique id of the order.
+	//
+	// Example: "o-1"
+	//
+	// Constraints: minLength: 2, maxLength: 16.
+	ID string `+"`"+`json:"id"`+"`")
+
+	assert.Contains(t, source, "\t// Constraints: minimum: 1, exclusiveMaximum: 100.\n\tQuantity *int")
+
+	// Deprecated types and properties
+	assert.Contains(t, source, "// Deprecated: The type is deprecated in the specification.\ntype LegacyOrder struct {")
+	assert.Contains(t, source, "\t// Deprecated: The property is deprecated in the specification.\n\tLegacyCode *string")
+
+	vet(t, "docs")
+}
@@ -0,0 +1,55 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamples(t *testing.T) {
+	_, files := generate(t, "examples", func(settings *generator.Settings) {
+		settings.UseExamples(true)
+	}, ".:examples/*.yaml")
+
+	source := files["examples/example_test.go"]
+
+	assert.Contains(t, source, `// ExampleProduct unmarshals the example of Product in the specification.
+func ExampleProduct() {
+	var v Product
+	if err := json.Unmarshal([]byte(`+"`"+`{"id":"p-1","name":"Ball","price":9.5}`+"`"+`), &v); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(types.CanonicalJSON(v))
+	// Output: {"id":"p-1","name":"Ball","price":9.5}
+}`)
+
+	assert.Contains(t, source, "func ExampleProduct_example2() {")
+	assert.Contains(t, source, "func ExampleShipment_express() {")
+	assert.Contains(t, source, "// Output: {\"carrier\":\"ups\",\"days\":1}")
+	assert.Contains(t, source, "func ExampleShipment_standard() {")
+	assert.Contains(t, source, "// Output: \"ups\"")
+
+	// Composed from the property examples
+	assert.Contains(t, source, "// Output: {\"color\":\"red\",\"text\":\"Fragile\"}")
+
+	run(t, "examples", "examples")
+}
+
+func TestExamplesShallCompile(t *testing.T) {
+	_, files := generate(t, "examplesnamed", func(settings *generator.Settings) {
+		settings.UseExamples(true)
+	}, ".:{anyof,allof}/*.yaml")
+
+	assert.Contains(t, files["anyof/example_test.go"], "func ExampleAPIUsage_example4() {")
+	assert.Contains(t, files["allof/example_test.go"], "func ExampleReport() {")
+
+	// The examples of APIUsage do not match the schema
+	assert.NotContains(t, files["anyof/example_test.go"], `// Output: {"name":"payload-size","value":100}`)
+
+	vet(t, "examplesnamed")
+	// Every generated package
+	run(t, "examplesnamed", "...")
+}
@@ -0,0 +1,190 @@
+package generatortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeShallHonorConstraints(t *testing.T) {
+	ctx, _ := generate(t, "fake", nil, ".:fake/*.yaml")
+
+	faker := fake.New(ctx, 42)
+	id := regexp.MustCompile(`^ACC-[0-9]{4}-[A-Z]{2}$`)
+
+	for i := 0; i < 50; i++ {
+		value, err := faker.Component("Account")
+		assert.Equal(t, nil, err)
+
+		account := value.(map[string]any)
+		assert.Regexp(t, id, account["id"])
+		assert.Contains(t, []any{"active", "suspended", "closed"}, account["status"])
+		assert.Contains(t, account["email"], "@")
+
+		roles := account["roles"].([]any)
+		assert.True(t, len(roles) >= 1 && len(roles) <= 3, roles)
+
+		seen := map[any]bool{}
+		for _, role := range roles {
+			assert.False(t, seen[role], "duplicate role: %v", role)
+			seen[role] = true
+		}
+
+		if level, ok := account["level"]; ok {
+			assert.Equal(t, 0, int(level.(int64))%5)
+			assert.True(t, level.(int64) >= 10 && level.(int64) <= 50, level)
+		}
+
+		if balance, ok := account["balance"]; ok {
+			assert.True(t, balance.(float64) >= 0 && balance.(float64) < 1000, balance)
+		}
+
+		labels := account["labels"].(map[string]any)
+		assert.NotEmpty(t, labels)
+
+		for _, label := range labels {
+			assert.True(t, len(label.(string)) <= 8, label)
+		}
+	}
+}
+
+func TestFakeShallBeReproducible(t *testing.T) {
+	ctx, _ := generate(t, "fakeseed", nil, ".:fake/*.yaml")
+
+	produce := func(seed int64) []string {
+		faker := fake.New(ctx, seed)
+		values := []string{}
+
+		for i := 0; i < 10; i++ {
+			value, err := faker.Component("Account")
+			assert.Equal(t, nil, err)
+
+			data, _ := json.Marshal(value)
+			values = append(values, string(data))
+		}
+
+		return values
+	}
+
+	assert.Equal(t, produce(7), produce(7))
+	assert.NotEqual(t, produce(7), produce(8))
+}
+
+func TestFakeShallLimitRecursion(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	ctx, _ := generate(t, "fakecycles", func(settings *generator.Settings) {
+		settings.UseSpec(filepath.Join(cwd, "testdata", "cycles", "api.yaml"), outputPackage+"/fakecycles")
+	})
+
+	faker := fake.New(ctx, 1).UseMaxDepth(2)
+
+	for i := 0; i < 20; i++ {
+		value, err := faker.Component("Node")
+		assert.Equal(t, nil, err)
+
+		data, _ := json.Marshal(value)
+		assert.True(t, strings.Count(string(data), "name") <= 7, string(data))
+	}
+
+	// Person and Company requires each other
+	_, err := faker.Component("Person")
+	assert.ErrorContains(t, err, "required properties are too deeply nested")
+}
+
+func TestFakeShallProduceAllComponents(t *testing.T) {
+	ctx, _ := generate(t, "fakeall", nil, ".:{anyof,allof}/*.yaml")
+
+	faker := fake.New(ctx, 3)
+
+	for _, name := range fake.Names(ctx) {
+		for i := 0; i < 10; i++ {
+			_, err := faker.Component(name)
+			assert.Equal(t, nil, err, name)
+		}
+	}
+
+	value, err := faker.Component("ChooseReporter")
+	assert.Equal(t, nil, err)
+	assert.Contains(t, []any{"ImportReport", "UsageReport"}, value.(map[string]any)["type"])
+}
+
+func TestFakeShallFillGeneratedTypes(t *testing.T) {
+	ctx, _ := generate(t, "fakefill", nil, ".:fake/*.yaml")
+
+	faker := fake.New(ctx, 11)
+	td := ctx.ResolveDefinition(ctx.GetSpecification().Components["Account"])
+
+	values := []string{}
+	for i := 0; i < 10; i++ {
+		var value any
+		assert.Equal(t, nil, faker.Fill(td, &value))
+
+		data, _ := json.Marshal(value)
+		values = append(values, fmt.Sprintf("%q", string(data)))
+	}
+
+	goTest(t, "fakefill", "fake", "fill_test.go", `package fake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFill(t *testing.T) {
+	for _, data := range []string{`+strings.Join(values, ", ")+`} {
+		var v Account
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v.Validate(); err != nil {
+			t.Fatal(data, err)
+		}
+	}
+}
+`)
+
+}
+
+func TestFakeShallMatchAllPatterns(t *testing.T) {
+	ctx, _ := generate(t, "fakepatterns", nil, ".:intersect/*.yaml")
+
+	faker := fake.New(ctx, 42)
+	lower, length := regexp.MustCompile(`^[a-z]+$`), regexp.MustCompile(`^.{3,5}$`)
+
+	for i := 0; i < 20; i++ {
+		value, err := faker.Component("Item")
+		assert.Equal(t, nil, err)
+
+		if code, ok := value.(map[string]any)["code"]; ok {
+			assert.Regexp(t, lower, code)
+			assert.Regexp(t, length, code)
+		}
+	}
+}
+
+func TestFakeNumberShallBeWithinNarrowRange(t *testing.T) {
+	faker := fake.New(nil, 7)
+
+	schema := openapi3.NewFloat64Schema().WithMin(0.001).WithMax(0.004)
+	for i := 0; i < 100; i++ {
+		value := faker.Number(schema)
+		assert.True(t, value >= 0.001 && value <= 0.004, "%v is out of range", value)
+	}
+
+	schema = openapi3.NewFloat64Schema().WithMin(1).WithMax(1.004).WithExclusiveMin(true)
+	for i := 0; i < 100; i++ {
+		value := faker.Number(schema)
+		assert.True(t, value > 1 && value <= 1.004, "%v is out of range", value)
+	}
+}
@@ -1,56 +1,27 @@
 package generatortest
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
-	"path/filepath"
 	"testing"
-
-	"github.com/mariotoffia/go-openapi/generator"
-	"github.com/mariotoffia/go-openapi/generator/gentypes"
-	"github.com/stretchr/testify/assert"
 )
 
-func TestSimpleAllOf(t *testing.T) {
-
-	// https://swagger.io/docs/specification/data-models/inheritance-and-polymorphism/
-	// https://github.com/getkin/kin-openapi
-	// https://github.com/deepmap/oapi-codegen
-	cwd, _ := os.Getwd()
-	gen := generator.NewSettings(generator.Templates{}).
-		UseModelPath(
-			filepath.Join(cwd, "./testdata/allof"),
-			"github.com/mariotoffia/go-openapi/generator/testdata/allof",
-		).
-		UseOutputPath("_output").
-		ToGenerator()
-
-	ctx := generator.GeneratorContext{}
-	err := gen.Generate(&ctx)
-	assert.Equal(t, nil, err)
-
-	spec := ctx.GetSpecification()
-
-	m := map[string]gentypes.TypeDefinition{}
-
-	for k, v := range spec.Components {
-		if v.Definition != nil {
-			m[k] = *v.Definition
-		}
+// TestGolden generates each test case in _testdata_ and compares the outcome with its golden
+// files, see `golden`. A new test case is added as a folder with the yaml models, and an
+// optional _case.json_, followed by running the test with `-update`.
+func TestGolden(t *testing.T) {
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-		if v.Reference != nil {
-			component := ctx.ResolveTypeDefinition(v.Reference)
-			if component != nil {
-				m[k] = *component
-			}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
 		}
-	}
 
-	data, err := json.Marshal(m)
-	if err != nil {
-		t.Error(err)
+		name := entry.Name()
+		t.Run(name, func(t *testing.T) {
+			golden(t, name)
+		})
 	}
-
-	fmt.Println(string(data))
 }
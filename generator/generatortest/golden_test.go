@@ -0,0 +1,195 @@
+package generatortest
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// update is set, using `go test ./generator/generatortest -run TestGolden -update`, to
+// regenerate the golden files instead of comparing against them.
+var update = flag.Bool("update", false, "update the golden files in testdata/<case>/golden")
+
+const (
+	// goldenDir is the folder, within a test case, of the golden files.
+	goldenDir = "golden"
+	// goldenCaseFile is the optional configuration of a test case, see `goldenCase`.
+	goldenCaseFile = "case.json"
+	// goldenSuffix is the suffix of the golden files of the generated go files such that
+	// these are not compiled.
+	goldenSuffix = ".golden"
+)
+
+// goldenCase is the configuration of a test case in _testdata/<case>/case.json_. Without
+// the file, all yaml files in the case folder are models rendered with the default settings.
+type goldenCase struct {
+	// Include are additional test cases that the models of this case references, e.g.
+	// _allof_.
+	Include []string `json:"include,omitempty"`
+	// Spec is the OpenAPI specification, relative to the case folder, to render instead of
+	// the models, e.g. _api.yaml_.
+	Spec string `json:"spec,omitempty"`
+	// Examples enables `Settings.UseExamples`.
+	Examples bool `json:"examples,omitempty"`
+	// Variants enables `Settings.UseRequestResponseVariants`.
+	Variants bool `json:"variants,omitempty"`
+	// Patch enables `Settings.UsePatchTypes`.
+	Patch bool `json:"patch,omitempty"`
+	// XML enables `Settings.UseXML`.
+	XML bool `json:"xml,omitempty"`
+	// DefaultsOnUnmarshal enables `Settings.UseDefaultsOnUnmarshal`.
+	DefaultsOnUnmarshal bool `json:"defaultsOnUnmarshal,omitempty"`
+}
+
+// golden generates the test case _testdata/<name>_ and compares the outcome with the golden
+// files in _testdata/<name>/golden_:
+//
+//   - _model.json_ is the resolved model, see `gentypes.MarshalModel`.
+//   - _diagnostics.txt_ is the diagnostics, one per line, if any.
+//   - _error.txt_ is the error when the generation is expected to fail.
+//   - _<path>.go.golden_ is each generated go file.
+//
+// The golden files are rewritten when the `-update` flag is set.
+func golden(t *testing.T, name string) {
+	cwd, _ := os.Getwd()
+	testdata := filepath.Join(cwd, "testdata")
+	dir := filepath.Join(testdata, name)
+	output := filepath.Join(cwd, "_output", "golden", name)
+	goPackage := outputPackage + "/golden/" + name
+
+	config := goldenCase{}
+	if data, err := os.ReadFile(filepath.Join(dir, goldenCaseFile)); err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("%s: %s", goldenCaseFile, err.Error())
+		}
+	}
+
+	os.RemoveAll(output)
+
+	settings := generator.NewSettings(generator.Templates{}).
+		UseOutputPath(output).
+		UseRequestResponseVariants(config.Variants).
+		UsePatchTypes(config.Patch).
+		UseXML(config.XML).
+		UseExamples(config.Examples).
+		UseDefaultsOnUnmarshal(config.DefaultsOnUnmarshal)
+
+	if config.Spec != "" {
+		settings.UseSpec(filepath.Join(dir, config.Spec), goPackage)
+	} else {
+		include := []string{".:" + name + "/*.yaml"}
+		for _, other := range config.Include {
+			include = append(include, ".:"+other+"/*.yaml")
+		}
+
+		settings.UseModelPath(testdata, goPackage).Include(include...)
+	}
+
+	actual := map[string]string{}
+
+	ctx := &generator.GeneratorContext{}
+	if err := settings.ToGenerator().Generate(ctx); err != nil {
+		actual["error.txt"] = relativeTo(testdata, err.Error()) + "\n"
+	} else {
+		actual["model.json"] = relativeTo(testdata, goldenModel(t, ctx))
+
+		if diagnostics := ctx.GetDiagnostics(); len(diagnostics) > 0 {
+			lines := make([]string, 0, len(diagnostics))
+			for _, diagnostic := range diagnostics {
+				lines = append(lines, relativeTo(testdata, diagnostic.String()))
+			}
+
+			actual["diagnostics.txt"] = strings.Join(lines, "\n") + "\n"
+		}
+
+		for _, file := range ctx.GetFiles() {
+			actual[filepath.ToSlash(file.Path)+goldenSuffix] = string(file.Content)
+		}
+	}
+
+	goldens := filepath.Join(dir, goldenDir)
+
+	if *update {
+		os.RemoveAll(goldens)
+
+		for path, content := range actual {
+			file := filepath.Join(goldens, filepath.FromSlash(path))
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return
+	}
+
+	expected := map[string]string{}
+	err := filepath.Walk(goldens, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(goldens, path)
+		expected[filepath.ToSlash(rel)] = string(data)
+
+		return nil
+	})
+
+	if os.IsNotExist(err) {
+		t.Fatalf("no golden files in testdata/%s/%s, run the test with -update to create them", name, goldenDir)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, sortedKeys(expected), sortedKeys(actual), "golden files of %s", name)
+
+	for path, content := range expected {
+		if generated, ok := actual[path]; ok {
+			assert.Equal(t, content, generated, "testdata/%s/%s/%s, run the test with -update if expected", name, goldenDir, path)
+		}
+	}
+}
+
+// goldenModel renders the model of _ctx_ as json, see `gentypes.MarshalModel`.
+func goldenModel(t *testing.T, ctx *generator.GeneratorContext) string {
+	data, err := gentypes.MarshalModel(ctx.Model(), gentypes.ModelFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// relativeTo replaces the absolute _path_ in _s_ with the name of the path such that the
+// golden files do not depend on where the repository is checked out.
+func relativeTo(path, s string) string {
+	return strings.ReplaceAll(s, filepath.ToSlash(path), filepath.Base(path))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
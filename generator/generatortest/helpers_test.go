@@ -0,0 +1,77 @@
+package generatortest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+// outputPackage is the go package of the _output_ folder where generated code is written.
+const outputPackage = "github.com/mariotoffia/go-openapi/generator/generatortest/_output"
+
+// generate runs the generator on the testdata modules matching the _include_ globs and writes
+// the generated go code into _output/<name>. The _configure_ may alter the settings.
+func generate(
+	t *testing.T,
+	name string,
+	configure func(settings *generator.Settings),
+	include ...string) (*generator.GeneratorContext, map[string]string) {
+
+	cwd, _ := os.Getwd()
+	output := filepath.Join(cwd, "_output", name)
+
+	os.RemoveAll(output)
+
+	settings := generator.NewSettings(generator.Templates{}).
+		UseModelPath(filepath.Join(cwd, "testdata"), outputPackage+"/"+name).
+		Include(include...).
+		UseOutputPath(output)
+
+	if configure != nil {
+		configure(settings)
+	}
+
+	ctx := &generator.GeneratorContext{}
+	if err := settings.ToGenerator().Generate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, file := range ctx.GetFiles() {
+		files[filepath.ToSlash(file.Path)] = string(file.Content)
+	}
+
+	return ctx, files
+}
+
+// vet runs `go vet` on the generated code in _output/<name> to verify that it compiles.
+func vet(t *testing.T, name string) {
+	cmd := exec.Command("go", "vet", "./"+filepath.Join("_output", name)+"/...")
+
+	out, err := cmd.CombinedOutput()
+	assert.Equal(t, nil, err, string(out))
+}
+
+// goTest writes the _source_ as _file_ into the generated package _pkg_ in _output/<name>
+// and runs `go test` on it.
+func goTest(t *testing.T, name, pkg, file, source string) {
+	dir := filepath.Join("_output", name, pkg)
+
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run(t, name, pkg)
+}
+
+// run runs `go test` on the generated package _pkg_ in _output/<name>.
+func run(t *testing.T, name, pkg string) {
+	cmd := exec.Command("go", "test", "./"+filepath.Join("_output", name, pkg))
+
+	out, err := cmd.CombinedOutput()
+	assert.Equal(t, nil, err, string(out))
+}
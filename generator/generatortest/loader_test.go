@@ -0,0 +1,48 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateShallReadChangedModels verifies that a model file changed between two
+// generations in the same process is read again, i.e. not served from a loader cache.
+func TestGenerateShallReadChangedModels(t *testing.T) {
+	models := t.TempDir()
+	model := filepath.Join(models, "shop.yaml")
+
+	write := func(content string) {
+		if err := os.WriteFile(model, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	components := func() map[string]bool {
+		ctx := &generator.GeneratorContext{}
+
+		err := generator.NewSettings(generator.Templates{}).
+			UseModelPath(models, outputPackage+"/loader").
+			Include(".:*.yaml").
+			ToGenerator().
+			Generate(ctx)
+
+		assert.Equal(t, nil, err)
+
+		names := map[string]bool{}
+		for name := range ctx.GetSpecification().Components {
+			names[name] = true
+		}
+
+		return names
+	}
+
+	write("Order:\n  type: object\n  properties:\n    id:\n      type: string\n")
+	assert.Equal(t, map[string]bool{"Order": true}, components())
+
+	write("Order:\n  type: object\n  properties:\n    id:\n      type: string\nItem:\n  type: string\n")
+	assert.Equal(t, map[string]bool{"Order": true, "Item": true}, components())
+}
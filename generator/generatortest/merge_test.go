@@ -0,0 +1,236 @@
+package generatortest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSchemaObjectsIntersectsConstraints(t *testing.T) {
+	to := openapi3.NewObjectSchema().
+		WithProperty("size", openapi3.NewIntegerSchema().WithMin(1).WithMax(100)).
+		WithProperty("color", openapi3.NewStringSchema().WithEnum("red", "green", "blue").WithMaxLength(10))
+
+	to.MinProps = 1
+	to.MaxProps = openapi3.Uint64Ptr(5)
+
+	from := openapi3.NewObjectSchema().
+		WithProperty("size", openapi3.NewFloat64Schema().WithMin(10).WithMax(50).WithExclusiveMax(true)).
+		WithProperty("color", openapi3.NewStringSchema().WithEnum("green", "blue", "black").WithMinLength(2)).
+		WithProperty("name", openapi3.NewStringSchema())
+
+	from.MinProps = 2
+	from.MaxProps = openapi3.Uint64Ptr(3)
+
+	merged, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, uint64(2), merged.MinProps)
+	assert.Equal(t, uint64(3), *merged.MaxProps)
+	assert.Equal(t, 3, len(merged.Properties))
+
+	size := merged.Properties["size"].Value
+	assert.Equal(t, "integer", size.Type)
+	assert.Equal(t, 10.0, *size.Min)
+	assert.Equal(t, 50.0, *size.Max)
+	assert.Equal(t, true, size.ExclusiveMax)
+
+	color := merged.Properties["color"].Value
+	assert.Equal(t, []any{"green", "blue"}, color.Enum)
+	assert.Equal(t, uint64(2), color.MinLength)
+	assert.Equal(t, uint64(10), *color.MaxLength)
+
+	// The input schemas are not altered
+	assert.Equal(t, 2, len(to.Properties))
+	assert.Equal(t, 1.0, *to.Properties["size"].Value.Min)
+}
+
+func TestMergeSchemaObjectsWithConflictingPropertyTypesShallFail(t *testing.T) {
+	to := openapi3.NewObjectSchema().WithProperty("id", openapi3.NewStringSchema())
+	from := openapi3.NewObjectSchema().WithProperty("id", openapi3.NewIntegerSchema())
+
+	_, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "property: id: type string conflicts with type integer")
+}
+
+func TestMergeSchemaObjectsWithUnsatisfiableConstraintsShallFail(t *testing.T) {
+	to := openapi3.NewObjectSchema().WithProperty("size", openapi3.NewIntegerSchema().WithMin(10))
+	from := openapi3.NewObjectSchema().WithProperty("size", openapi3.NewIntegerSchema().WithMax(5))
+
+	_, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "minimum 10 is not less than maximum 5")
+
+	to = openapi3.NewObjectSchema().WithProperty("kind", openapi3.NewStringSchema().WithEnum("a"))
+	from = openapi3.NewObjectSchema().WithProperty("kind", openapi3.NewStringSchema().WithEnum("b"))
+
+	_, err = generator.MergeSchemaObjects(nil, to, from)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestMergeSchemaObjectsKeepsBothNotAndPattern(t *testing.T) {
+	to := openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewStringSchema().WithPattern("^[a-z]+$"))
+
+	to.Not = openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithProperty("code", openapi3.NewStringSchema().WithEnum("admin")))
+
+	from := openapi3.NewObjectSchema().
+		WithProperty("code", openapi3.NewStringSchema().WithPattern("^.{3,5}$"))
+
+	from.Not = openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithProperty("code", openapi3.NewStringSchema().WithEnum("guest")))
+
+	merged, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, openapi3.SchemaRefs{to.Not, from.Not}, merged.Not.Value.AnyOf)
+	assert.Equal(t, []string{"^[a-z]+$", "^.{3,5}$"}, generator.SchemaPatterns(merged.Properties["code"].Value))
+
+	// The input schemas are not altered
+	assert.Equal(t, 0, len(to.Properties["code"].Value.Extensions))
+}
+
+func TestMergeSchemaObjectsMergesInlineConstraintsIntoReference(t *testing.T) {
+	size := openapi3.NewIntegerSchema().WithMax(100)
+
+	to := openapi3.NewObjectSchema().
+		WithPropertyRef("size", &openapi3.SchemaRef{Ref: "#/Size", Value: size})
+
+	from := openapi3.NewObjectSchema().
+		WithProperty("size", &openapi3.Schema{Min: openapi3.Float64Ptr(5)})
+
+	merged, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.Equal(t, nil, err)
+
+	ref := merged.Properties["size"]
+	assert.Equal(t, "", ref.Ref)
+	assert.Equal(t, "integer", ref.Value.Type)
+	assert.Equal(t, 5.0, *ref.Value.Min)
+	assert.Equal(t, 100.0, *ref.Value.Max)
+	assert.Equal(t, (*float64)(nil), size.Min)
+}
+
+func TestMergeSchemaObjectsReturnsMergedSchemaWhenUnsatisfiable(t *testing.T) {
+	to := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewStringSchema()).
+		WithProperty("size", openapi3.NewIntegerSchema().WithMin(10))
+
+	from := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema()).
+		WithProperty("size", openapi3.NewIntegerSchema().WithMax(5))
+
+	merged, err := generator.MergeSchemaObjects(nil, to, from)
+
+	var unsatisfiable *generator.UnsatisfiableError
+	assert.True(t, errors.As(err, &unsatisfiable))
+	assert.Equal(t, []string{
+		"property: id: type string conflicts with type integer",
+		"property: size: minimum 10 is not less than maximum 5",
+	}, unsatisfiable.Reasons)
+
+	assert.Equal(t, "string", merged.Properties["id"].Value.Type)
+}
+
+func TestUnsatisfiableAllOfShallAddDiagnostic(t *testing.T) {
+	ctx, _ := generate(t, "intersect", nil, ".:intersect/*.yaml")
+
+	diagnostics := ctx.GetDiagnostics()
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Contains(t, diagnostics[0].String(), "intersect/intersect.yaml:29:3: intersect/intersect#/Unsatisfiable")
+
+	goTest(t, "intersect", "intersect", "intersect_test.go", `package intersect
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, code := range []string{"abc", "users"} {
+		if err := (Item{Code: &code}).Validate(); err != nil {
+			t.Errorf("%s: %s", code, err)
+		}
+	}
+
+	for _, code := range []string{"admin", "guest", "ab", "Abc", "abcdef"} {
+		if err := (Item{Code: &code}).Validate(); err == nil {
+			t.Errorf("%s is not a valid code", code)
+		}
+	}
+
+	for _, size := range []int{4, 101} {
+		if err := (Item{Size: &size}).Validate(); err == nil {
+			t.Errorf("%d is not a valid size", size)
+		}
+	}
+
+	for _, kind := range []string{"cat", "dog"} {
+		if err := (Pet{Kind: &kind}).Validate(); err == nil {
+			t.Errorf("%s is not a valid kind", kind)
+		}
+	}
+
+	fish := "fish"
+	if err := (Pet{Kind: &fish}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
+`)
+}
+
+func TestMergeSchemaObjectsIntersectsObjectAndArrayEnums(t *testing.T) {
+	origin, unit := map[string]any{"x": 0.0, "y": 0.0}, map[string]any{"x": 1.0, "y": 1.0}
+
+	to := openapi3.NewObjectSchema().
+		WithProperty("point", openapi3.NewObjectSchema().WithEnum(origin, unit)).
+		WithProperty("pair", openapi3.NewArraySchema().WithEnum([]any{1.0, 2.0}, []any{3.0, 4.0}))
+
+	from := openapi3.NewObjectSchema().
+		WithProperty("point", openapi3.NewObjectSchema().WithEnum(map[string]any{"x": 1.0, "y": 1.0})).
+		WithProperty("pair", openapi3.NewArraySchema().WithEnum([]any{3.0, 4.0}))
+
+	merged, err := generator.MergeSchemaObjects(nil, to, from)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, []any{unit}, merged.Properties["point"].Value.Enum)
+	assert.Equal(t, []any{[]any{3.0, 4.0}}, merged.Properties["pair"].Value.Enum)
+}
+
+func TestMergeSchemaObjectsTreatsUntypedSchemasAsObjects(t *testing.T) {
+	required := &openapi3.Schema{Required: []string{"id"}}
+	properties := &openapi3.Schema{Properties: openapi3.Schemas{
+		"name": openapi3.NewSchemaRef("", openapi3.NewStringSchema()),
+	}}
+
+	merged, err := generator.MergeSchemaObjects(nil, required, properties)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"id"}, merged.Required)
+	assert.Equal(t, 1, len(merged.Properties))
+
+	merged, err = generator.MergeSchemaObjects(nil, openapi3.NewObjectSchema(), properties)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "object", merged.Type)
+
+	_, err = generator.MergeSchemaObjects(nil, required, openapi3.NewStringSchema())
+	assert.NotEqual(t, nil, err)
+}
+
+func TestMergeSchemaObjectsCombinesMultipleOf(t *testing.T) {
+	for _, tc := range []struct{ to, from, expected float64 }{
+		{0.1, 0.3, 0.3},
+		{0.3, 0.1, 0.3},
+		{0.2, 0.3, 0.6},
+		{4, 6, 12},
+		{0.25, 1.0 / 3, 1},
+		{0.001, 0.0007, 0.007},
+	} {
+		to := openapi3.NewFloat64Schema()
+		to.MultipleOf = openapi3.Float64Ptr(tc.to)
+		from := openapi3.NewFloat64Schema()
+		from.MultipleOf = openapi3.Float64Ptr(tc.from)
+
+		merged, err := generator.IntersectSchemas(nil, to, from, "")
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tc.expected, *merged.MultipleOf, "%v and %v", tc.to, tc.from)
+	}
+}
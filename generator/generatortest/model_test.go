@@ -0,0 +1,50 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelShallRoundTrip(t *testing.T) {
+	ctx, _ := generate(t, "model", nil, ".:{anyof,allof}/*.yaml")
+
+	for _, format := range []gentypes.ModelFormat{gentypes.ModelFormatJSON, gentypes.ModelFormatYAML} {
+		data, err := gentypes.MarshalModel(ctx.Model(), format)
+		assert.Equal(t, nil, err)
+
+		model, err := gentypes.UnmarshalModel(data, format)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, gentypes.ModelVersion, model.Version)
+
+		again, err := gentypes.MarshalModel(model, format)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, string(data), string(again), format)
+
+		reporter := model.ResolveDefinition(model.Specification.Components["ChooseReporter"])
+		if !assert.NotNil(t, reporter) {
+			continue
+		}
+
+		assert.Equal(t, "github.com/mariotoffia/go-openapi/generator/generatortest/_output/model/anyof", reporter.GoPackage)
+		assert.Len(t, reporter.DiscriminatorComponents, 2)
+		assert.Equal(t, "type", reporter.DiscriminatorComponents[0].Discriminator)
+
+		usage := model.ResolveDefinition(&reporter.DiscriminatorComponents[1].ComponentDefinition)
+		if !assert.NotNil(t, usage) {
+			continue
+		}
+
+		assert.Equal(t, "UsageReport", usage.ID.TypeName)
+		assert.Equal(t, "Report", usage.Composition[0].Reference.TypeName)
+		assert.NotNil(t, model.ResolveDefinition(&usage.Composition[0].ComponentDefinition))
+		assert.NotEmpty(t, usage.Schema.AllOf)
+	}
+}
+
+func TestModelShallRejectUnknownVersion(t *testing.T) {
+	_, err := gentypes.UnmarshalModel([]byte(`{"version": "0", "names": {}, "components": [], "types": []}`), gentypes.ModelFormatJSON)
+
+	assert.ErrorContains(t, err, "unsupported model version: 0, expected: 1")
+}
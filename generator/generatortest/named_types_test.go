@@ -0,0 +1,155 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedArrayAndPrimitiveComponents(t *testing.T) {
+	ctx, files := generate(t, "named", nil, ".:{anyof,allof}/*.yaml")
+
+	usage := ctx.ResolveTypeDefinition(ctx.GetSpecification().Components["Usage"].Reference)
+	if assert.NotNil(t, usage) {
+		assert.True(t, usage.IsArray())
+		assert.Equal(t, "UsageType", usage.Items.Reference.TypeName)
+	}
+
+	source := files["anyof/usage_report.go"]
+
+	assert.Contains(t, source, "type Usage []UsageType")
+	assert.Contains(t, source, "type ComputeType string")
+	assert.Contains(t, source, `ComputeTypeLambda  ComputeType = "lambda"`)
+
+	// Properties referencing named types use them instead of inlining
+	assert.Contains(t, source, "ComputeType ComputeType `json:\"computeType\"`")
+	assert.Contains(t, source, "Usage   Usage   `json:\"usage\"`")
+
+	// Named types have their own validation
+	assert.Contains(t, source, "func (v Usage) Validate() error")
+	assert.Contains(t, source, "func (v ComputeType) Validate() error")
+
+	// Types in other packages are imported
+	assert.Contains(t, files["anyof/choose_reporter.go"], "*allof.ImportReport")
+
+	vet(t, "named")
+}
+
+// TestSpecificationShallContainReferencedComponents verifies that components created when
+// referenced by another component, before being processed themselves, are part of the
+// specification.
+func TestSpecificationShallContainReferencedComponents(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "api.yaml")
+	names := []string{"A", "B", "C", "D", "E", "F"}
+
+	content := "openapi: 3.0.3\ninfo:\n  title: chain\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n"
+	for i, name := range names {
+		content += "    " + name + ":\n      type: object\n"
+		if i+1 < len(names) {
+			content += "      properties:\n        next:\n          $ref: '#/components/schemas/" + names[i+1] + "'\n"
+		}
+	}
+
+	if err := os.WriteFile(spec, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &generator.GeneratorContext{}
+	err := generator.NewSettings(generator.Templates{}).
+		UseSpec(spec, outputPackage+"/chain").
+		ToGenerator().
+		Generate(ctx)
+
+	assert.Equal(t, nil, err)
+
+	for _, name := range names {
+		assert.NotNil(t, ctx.ResolveDefinition(ctx.GetSpecification().Components[name]), name)
+	}
+}
+
+func TestReferenceAboveRootPathShallFail(t *testing.T) {
+	dir := t.TempDir()
+	models := filepath.Join(dir, "models")
+
+	for file, content := range map[string]string{
+		"models/a.yaml": "A:\n  type: object\n  properties:\n    x:\n      $ref: \"../outside.yaml#/X\"\n",
+		"outside.yaml":  "X:\n  type: string\n",
+	} {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755)
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := generator.NewSettings(generator.Templates{}).
+		UseModelPath(models, outputPackage+"/aboveroot").
+		Include(".:*.yaml").
+		ToGenerator().
+		Generate(&generator.GeneratorContext{})
+
+	assert.ErrorContains(t, err, "reference ../outside.yaml#/X")
+	assert.ErrorContains(t, err, "is above root path")
+}
+
+func TestNamedMappedPrimitivesShallValidate(t *testing.T) {
+	_, files := generate(t, "timestamps", nil, ".:timestamps/*.yaml")
+
+	source := files["timestamps/timestamps.go"]
+
+	assert.Contains(t, source, "type Timestamp time.Time")
+	assert.Contains(t, source, "func (v Timestamp) Validate() error {")
+	assert.Contains(t, source, "func (v *Day) UnmarshalText(data []byte) error {")
+
+	goTest(t, "timestamps", "timestamps", "timestamps_test.go", `package timestamps
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mariotoffia/go-openapi/types"
+)
+
+func TestValidate(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal([]byte(`+"`"+`{"at":"2023-01-02T10:00:00Z","day":"2023-01-02"}`+"`"+`), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Time(event.At).Hour() != 10 || event.Day == nil {
+		t.Fatalf("expected the time and day to be unmarshalled: %+v", event)
+	}
+
+	if err := event.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil || string(data) != `+"`"+`{"at":"2023-01-02T10:00:00Z","day":"2023-01-02"}`+"`"+` {
+		t.Errorf("expected the event to marshal as in the json: %s %v", data, err)
+	}
+
+	local := Timestamp(time.Date(2023, 1, 2, 10, 0, 0, 0, time.FixedZone("CET", 3600)))
+	if err := local.Validate(); err == nil {
+		t.Error("a time that is not in UTC shall not match the pattern")
+	}
+
+	if err := (Event{At: local}).Validate(); err == nil {
+		t.Error("the at property shall be validated")
+	}
+
+	created := time.Time(local)
+	if err := (Event{At: Timestamp(time.Time{}.UTC()), Created: &created}).Validate(); err == nil {
+		t.Error("the created property shall be validated")
+	}
+
+	if err := Day(types.DateOf(time.Date(1999, 1, 2, 0, 0, 0, 0, time.UTC))).Validate(); err == nil {
+		t.Error("a day before 2000 shall not match the pattern")
+	}
+}
+`)
+}
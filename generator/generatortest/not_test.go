@@ -0,0 +1,62 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotKeyword(t *testing.T) {
+	ctx, files := generate(t, "not", nil, ".:not/*.yaml")
+
+	diagnostics := []string{}
+	for _, diagnostic := range ctx.GetDiagnostics() {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	assert.ElementsMatch(t, []string{
+		"not/not#/Impossible: not excludes both true and false, no value is valid",
+		"not/not#/Nothing: not matches every string value, no value is valid",
+		// The enum values are objects
+		"not/not#/Origin: not excludes every enum value, no value is valid",
+	}, diagnostics)
+
+	assert.Contains(t, files["not/not.go"], "func (v Username) Validate() error {")
+
+	goTest(t, "not", "not", "not_test.go", `package not
+
+import (
+	"testing"
+)
+
+func TestNot(t *testing.T) {
+	if err := Username("admin").Validate(); err == nil {
+		t.Fatal("expected a reserved username to fail")
+	}
+
+	if err := Username("alice").Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	short, long := "al", "alice"
+
+	if err := (Member{Username: "alice", Nickname: &short}).Validate(); err == nil {
+		t.Fatal("expected a short nickname to fail")
+	}
+
+	if err := (Member{Username: "alice", Nickname: &long}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	banned, allowed := true, false
+
+	if err := (Member{Username: "alice", Banned: &banned}).Validate(); err == nil {
+		t.Fatal("expected a banned member to fail")
+	}
+
+	if err := (Member{Username: "alice", Banned: &allowed}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+`)
+}
@@ -0,0 +1,100 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalGeneric(t *testing.T) {
+	_, files := generate(t, "optional", func(settings *generator.Settings) {
+		settings.UseOptionalPolicy(generator.OptionalGeneric)
+	}, ".:optional/*.yaml")
+
+	source := files["optional/optional.go"]
+
+	assert.Contains(t, source, "ID  types.Nullable[string] `json:\"id\"`")
+	assert.Contains(t, source, "Name     types.Optional[string]   `json:\"name,omitempty\"`")
+	assert.Contains(t, source, "Tags     types.Nullable[[]string] `json:\"tags,omitempty\"`")
+	assert.Contains(t, source, "Note     *string                  `json:\"note,omitempty\"`")
+
+	goTest(t, "optional", "optional", "optional_test.go", `package optional
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/types"
+)
+
+func TestOptional(t *testing.T) {
+	var patch UserPatch
+	if err := json.Unmarshal([]byte(`+"`"+`{"id":"u1","nickname":null,"age":0}`+"`"+`), &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	if patch.Name.Present || !patch.Nickname.Null || patch.Age != types.NewOptional(0) {
+		t.Fatalf("expected absent, null and value to be distinguished: %+v", patch)
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `+"`"+`{"age":0,"id":"u1","nickname":null}`+"`"+` {
+		t.Fatalf("expected absent properties to be omitted: %s", string(data))
+	}
+
+	if err := patch.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (UserPatch{}).Validate(); err == nil {
+		t.Fatal("expected the required id to be validated")
+	}
+
+	if err := (UserPatch{ID: types.NewNull[string](), Name: types.NewOptional("a")}).Validate(); err == nil {
+		t.Fatal("expected a present name to be validated")
+	}
+
+	defaults := NewUserPatch(types.NewNull[string]())
+	if defaults.Age != types.NewOptional(18) {
+		t.Fatalf("expected the default to be applied: %+v", defaults)
+	}
+
+	var admin AdminPatch
+	if err := json.Unmarshal([]byte(`+"`"+`{"id":null,"level":null}`+"`"+`), &admin); err != nil {
+		t.Fatal(err)
+	}
+
+	if !admin.ID.Null || !admin.Level.Null {
+		t.Fatalf("expected null in the composition: %+v", admin)
+	}
+
+	if data, err = json.Marshal(admin); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `+"`"+`{"id":null,"level":null}`+"`"+` {
+		t.Fatalf("expected absent properties in the composition to be omitted: %s", string(data))
+	}
+}
+`)
+}
+
+func TestOptionalValue(t *testing.T) {
+	_, files := generate(t, "optionalvalue", func(settings *generator.Settings) {
+		settings.UseOptionalPolicy(generator.OptionalValue)
+	}, ".:optional/*.yaml")
+
+	source := files["optional/optional.go"]
+
+	assert.Contains(t, source, "// Constraints: minLength: 2.\n\tName     string   `json:\"name,omitempty\"`")
+	assert.Contains(t, source, "Nickname *string  `json:\"nickname,omitempty\"`")
+	assert.Contains(t, source, "Address *Address `json:\"address,omitempty\"`")
+	assert.Contains(t, source, "if v.Name != \"\" {")
+
+	vet(t, "optionalvalue")
+}
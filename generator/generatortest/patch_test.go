@@ -0,0 +1,118 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchTypes(t *testing.T) {
+	_, files := generate(t, "patch", func(settings *generator.Settings) {
+		settings.UsePatchTypes(true)
+	}, ".:patch/*.yaml")
+
+	source := files["patch/patch.go"]
+
+	assert.Contains(t, source, "type PersonPatch struct")
+	assert.Contains(t, source, "Address  types.Nullable[AddressPatch]                      `json:\"address,omitempty\"`")
+	assert.Contains(t, source, "Labels   types.Nullable[map[string]types.Nullable[string]] `json:\"labels,omitempty\"`")
+	assert.Contains(t, source, "func (v Person) Apply(patch PersonPatch) (Person, error) {")
+	assert.Contains(t, source, "func DiffPerson(from, to Person) PersonPatch {")
+
+	goTest(t, "patch", "patch", "patch_test.go", `package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	age, nickname, city := 42, "bob", "Stockholm"
+
+	person := Person{
+		Name:     "Robert",
+		Age:      &age,
+		Nickname: &nickname,
+		Address:  &Address{Street: "Main", City: &city},
+		Labels:   map[string]string{"a": "1", "b": "2"},
+	}
+
+	var patch PersonPatch
+	if err := json.Unmarshal([]byte(`+"`"+`{"nickname":null,"address":{"street":"Side"},"labels":{"a":null,"c":"3"}}`+"`"+`), &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := patch.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := person.Apply(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patched.Nickname != nil || *patched.Age != 42 || patched.Address.Street != "Side" || *patched.Address.City != city ||
+		!reflect.DeepEqual(patched.Labels, map[string]string{"b": "2", "c": "3"}) {
+		t.Fatalf("unexpected patch result: %+v", patched)
+	}
+
+	if person.Nickname == nil || person.Labels["a"] != "1" {
+		t.Fatal("expected the original to be unchanged")
+	}
+
+	if _, err := person.Apply(PersonPatch{Name: patch.Nickname}); err == nil {
+		t.Fatal("expected removing a required property to fail")
+	}
+
+	diff := DiffPerson(person, patched)
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `+"`"+`{"address":{"street":"Side"},"labels":{"a":null,"c":"3"},"nickname":null}`+"`"+` {
+		t.Fatalf("unexpected diff: %s", string(data))
+	}
+
+	roundtrip, err := person.Apply(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(roundtrip, patched) {
+		t.Fatalf("expected the diff to recreate the patched value: %+v", roundtrip)
+	}
+
+	var name PersonPatch
+	if err := json.Unmarshal([]byte(`+"`"+`{"name":"Alice"}`+"`"+`), &name); err != nil {
+		t.Fatal(err)
+	}
+
+	employee := Employee{Person: person}
+	if applied, err := employee.Apply(EmployeePatch{Name: name.Name}); err != nil || applied.Name != "Alice" {
+		t.Fatalf("expected the composed properties to be patched: %+v %v", applied, err)
+	}
+}
+`)
+}
+
+func TestPatchTypesOfCyclicComponents(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	_, files := generate(t, "patchcycles", func(settings *generator.Settings) {
+		settings.UseSpec(filepath.Join(cwd, "testdata", "cycles", "api.yaml"), outputPackage+"/patchcycles").
+			UsePatchTypes(true).
+			UseOptionalPolicy(generator.OptionalGeneric)
+	})
+
+	source := files["api.go"]
+
+	assert.Contains(t, source, "Parent   types.Nullable[*NodePatch] `json:\"parent,omitempty\"`")
+
+	vet(t, "patchcycles")
+}
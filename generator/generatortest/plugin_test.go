@@ -0,0 +1,124 @@
+package generatortest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// pluginEnv is set when the test binary is run as a plugin, see `testPlugin`.
+const pluginEnv = "GO_OPENAPI_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(pluginEnv) != "" {
+		if err := generator.ServePlugin(os.Stdin, os.Stdout, testPlugin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// testPlugin renders a _components.txt_ with the go package of each named component. The
+// _path_ parameter overrides the path of the file and _fail_ returns an error.
+func testPlugin(request *generator.PluginRequest, model *gentypes.Model) ([]generator.PluginFile, error) {
+	if message, ok := request.Parameters["fail"]; ok {
+		return nil, fmt.Errorf("%s", message)
+	}
+
+	names := make([]string, 0, len(model.Specification.Components))
+	for name := range model.Specification.Components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines := []string{}
+	for _, name := range names {
+		td := model.ResolveDefinition(model.Specification.Components[name])
+		lines = append(lines, fmt.Sprintf("%s %s", name, td.GoPackage))
+	}
+
+	path := "components.txt"
+	if override, ok := request.Parameters["path"]; ok {
+		path = override
+	}
+
+	return []generator.PluginFile{{Path: path, Content: strings.Join(lines, "\n") + "\n"}}, nil
+}
+
+// testPluginOf returns the test binary as a plugin with the _parameters_.
+func testPluginOf(t *testing.T, parameters map[string]string) generator.Plugin {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return generator.Plugin{
+		Name:       "test",
+		Command:    executable,
+		Env:        []string{pluginEnv + "=1"},
+		Parameters: parameters,
+	}
+}
+
+func TestPluginShallWriteFiles(t *testing.T) {
+	_, files := generate(t, "plugin", func(settings *generator.Settings) {
+		settings.UsePlugins(testPluginOf(t, nil))
+	}, ".:allof/*.yaml")
+
+	pkg := outputPackage + "/plugin/allof"
+	expected := fmt.Sprintf(
+		"ImportReport %[1]s\nImportReportBody %[1]s\nReport %[1]s\nReportType %[1]s\n", pkg,
+	)
+
+	assert.Equal(t, expected, files["components.txt"])
+	assert.Contains(t, files, "allof/report.go")
+
+	data, err := os.ReadFile(filepath.Join("_output", "plugin", "components.txt"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expected, string(data))
+}
+
+func TestPluginErrorShallFailGeneration(t *testing.T) {
+	cases := []struct {
+		parameters map[string]string
+		error      string
+	}{
+		{map[string]string{"fail": "no mapper for Report"}, "plugin: test failed: no mapper for Report"},
+		{map[string]string{"path": "../outside.txt"}, "plugin: test file: ../outside.txt is not within the output path"},
+		{map[string]string{"path": "allof/report.go"}, "plugin: test file: allof/report.go is already generated by the generator"},
+	}
+
+	cwd, _ := os.Getwd()
+
+	for _, c := range cases {
+		settings := generator.NewSettings(generator.Templates{}).
+			UseModelPath(filepath.Join(cwd, "testdata"), outputPackage+"/pluginerror").
+			Include(".:allof/*.yaml").
+			UseOutputPath(filepath.Join(cwd, "_output", "pluginerror")).
+			UsePlugins(testPluginOf(t, c.parameters))
+
+		err := settings.ToGenerator().Generate(&generator.GeneratorContext{})
+		assert.EqualError(t, err, c.error)
+	}
+}
+
+func TestPluginShallRejectUnknownProtocolVersion(t *testing.T) {
+	var out strings.Builder
+
+	err := generator.ServePlugin(strings.NewReader(`{"version": "0"}`), &out, testPlugin)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\"error\":\"unsupported plugin protocol version: 0, expected: 1\"}\n", out.String())
+}
@@ -0,0 +1,77 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingResolver counts the registered and resolved components.
+type countingResolver struct {
+	*gentypes.ReferenceResolverImpl
+	registered int
+	resolved   int
+}
+
+func (r *countingResolver) RegisterComponent(cd *gentypes.ComponentDefinition) {
+	r.registered++
+	r.ReferenceResolverImpl.RegisterComponent(cd)
+}
+
+func (r *countingResolver) ResolveComponent(ref *gentypes.ComponentReference) *gentypes.ComponentDefinition {
+	r.resolved++
+	return r.ReferenceResolverImpl.ResolveComponent(ref)
+}
+
+func TestCustomResolverShallBeUsed(t *testing.T) {
+	resolver := &countingResolver{ReferenceResolverImpl: gentypes.NewReferenceResolver()}
+
+	ctx, files := generate(t, "resolver", func(settings *generator.Settings) {
+		settings.UseReferenceResolver(resolver)
+	}, ".:{anyof,allof}/*.yaml")
+
+	assert.Equal(t, gentypes.ReferenceResolver(resolver), ctx.GetResolver())
+	assert.Equal(t, len(resolver.Components()), resolver.registered)
+	assert.NotZero(t, resolver.resolved)
+	assert.Contains(t, files["anyof/usage_report.go"], "type UsageReport struct {")
+
+	vet(t, "resolver")
+}
+
+func TestResolverShallFindReferencesToComponent(t *testing.T) {
+	ctx, _ := generate(t, "references", nil, ".:{anyof,allof}/*.yaml")
+
+	report := ctx.ResolveDefinition(ctx.GetSpecification().Components["Report"])
+	if !assert.NotNil(t, report) {
+		return
+	}
+
+	names := []string{}
+	for _, cd := range ctx.GetResolver().ReferencesTo(&report.ID) {
+		names = append(names, cd.ID.TypeName)
+	}
+
+	assert.Equal(t, []string{"ImportReport", "UsageReport"}, names)
+}
+
+func TestResolverShallBeSharedAcrossGenerations(t *testing.T) {
+	resolver := gentypes.NewReferenceResolver()
+
+	_, shared := generate(t, "sharedallof", func(settings *generator.Settings) {
+		settings.UseReferenceResolver(resolver)
+	}, ".:allof/*.yaml")
+
+	assert.Contains(t, shared, "allof/report.go")
+
+	_, files := generate(t, "sharedanyof", func(settings *generator.Settings) {
+		settings.UseReferenceResolver(resolver)
+	}, ".:anyof/*.yaml")
+
+	assert.NotContains(t, files, "allof/report.go")
+	assert.Contains(t, files["anyof/usage_report.go"], `"`+outputPackage+`/sharedallof/allof"`)
+
+	vet(t, "sharedallof")
+	vet(t, "sharedanyof")
+}
@@ -0,0 +1,104 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTripTests(t *testing.T) {
+	_, files := generate(t, "roundtrip", func(settings *generator.Settings) {
+		settings.UseRoundTripTests(true)
+	}, ".:examples/*.yaml")
+
+	source := files["examples/roundtrip_test.go"]
+
+	assert.Contains(t, source, "var roundTripProduct = []string{\n"+
+		"\t`{\"id\":\"p-1\",\"name\":\"Ball\",\"price\":9.5}`,\n")
+
+	assert.Contains(t, source, `// TestRoundTripProduct verifies that the json of Product is stable when unmarshalled and
+// marshalled again.
+func TestRoundTripProduct(t *testing.T) {
+	for _, data := range roundTripProduct {
+		var v Product
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+
+		if err := v.Validate(); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+`)
+
+	assert.Contains(t, source, `// FuzzUnmarshalProduct verifies that unmarshal, validate and marshal of Product never panics.
+func FuzzUnmarshalProduct(f *testing.F) {
+	for _, data := range roundTripProduct {
+		f.Add([]byte(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v Product
+		if err := json.Unmarshal(data, &v); err != nil {
+			return
+		}
+
+		_ = v.Validate()
+
+		if _, err := json.Marshal(v); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+	})
+}`)
+
+	run(t, "roundtrip", "examples")
+}
+
+func TestRoundTripTestsShallIncludeFakeValues(t *testing.T) {
+	ctx, files := generate(t, "roundtripfake", func(settings *generator.Settings) {
+		settings.UseRoundTripTests(true).UseFakeValues(5, fake.Factory(1))
+	}, ".:{fake,maps,arrays,variants}/*.yaml", ".:{anyof,allof}/*.yaml")
+
+	for _, diagnostic := range ctx.GetDiagnostics() {
+		assert.NotContains(t, diagnostic.Message, "round-trip")
+	}
+
+	source := files["fake/roundtrip_test.go"]
+	start := strings.Index(source, "var roundTripAccount = []string{")
+	end := start + strings.Index(source[start:], "\n}")
+
+	assert.Equal(t, 5, strings.Count(source[start:end], `"status":`))
+
+	// An optional array is validated when present
+	assert.Contains(t, files["arrays/arrays.go"], "\tif v.Tags != nil {\n")
+
+	for _, pkg := range []string{"fake", "maps", "arrays", "variants", "anyof", "allof"} {
+		run(t, "roundtripfake", pkg)
+	}
+}
+
+func TestRoundTripTestsShallSkipUnproducibleTypes(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	ctx, files := generate(t, "roundtripcycles", func(settings *generator.Settings) {
+		settings.
+			UseSpec(filepath.Join(cwd, "testdata", "cycles", "api.yaml"), outputPackage+"/roundtripcycles").
+			UseRoundTripTests(true).
+			UseFakeValues(2, fake.Factory(1))
+	})
+
+	diagnostics := []string{}
+	for _, diagnostic := range ctx.GetDiagnostics() {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	assert.Len(t, diagnostics, 2)
+	assert.Contains(t, diagnostics[0], "no fake values in round-trip tests")
+	assert.Contains(t, files["roundtrip_test.go"], "var roundTripPerson = []string{}")
+
+	run(t, "roundtripcycles", "")
+}
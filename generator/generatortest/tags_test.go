@@ -0,0 +1,52 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRules(t *testing.T) {
+	_, files := generate(t, "tags", func(settings *generator.Settings) {
+		settings.UseTagRules(
+			generator.TagRule{Key: "yaml"},
+			generator.TagRule{Key: "bson", Casing: generator.TagCasingSnake},
+			generator.TagRule{Key: "db", Casing: generator.TagCasingSnake, OmitEmpty: generator.OmitEmptyNever},
+			generator.TagRule{Key: "xml", Template: "{{.Name}}{{if not .Required}},omitempty{{end}}", Casing: generator.TagCasingPascal},
+			generator.ValidateTagRule(),
+		)
+	}, ".:tags/*.yaml")
+
+	source := files["tags/tags.go"]
+
+	assert.Contains(t, source,
+		"AccountID types.UUID `json:\"accountId\" yaml:\"accountId\" bson:\"_id\" db:\"account_id\" xml:\"AccountId\" validate:\"required,uuid\" dynamodbav:\"pk\"`",
+	)
+	assert.Contains(t, source,
+		"DisplayName string         `json:\"displayName\" yaml:\"displayName\" bson:\"display_name\" db:\"display_name\" xml:\"DisplayName\" validate:\"required,min=1,max=64\"`",
+	)
+	assert.Contains(t, source,
+		"Balance *int `json:\"balance,omitempty\" yaml:\"balance,omitempty\" bson:\"balance,omitempty\" db:\"balance\" xml:\"Balance,omitempty\" validate:\"omitempty,gte=0\"`",
+	)
+	assert.Contains(t, source, "validate:\"omitempty,oneof=active closed\"")
+
+	vet(t, "tags")
+}
+
+func TestTagRuleShallNotRenderJSON(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	err := generator.NewSettings(generator.Templates{}).
+		UseModelPath(filepath.Join(cwd, "testdata"), outputPackage+"/tagsjson").
+		Include(".:tags/*.yaml").
+		UseOutputPath(t.TempDir()).
+		UseTagRules(generator.TagRule{Key: "json"}).
+		ToGenerator().
+		Generate(&generator.GeneratorContext{})
+
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "can not render the json tag")
+}
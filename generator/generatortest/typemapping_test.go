@@ -0,0 +1,71 @@
+package generatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeMapping(t *testing.T) {
+	cwd, _ := os.Getwd()
+	gen := generator.NewSettings(generator.Templates{}).
+		UseModelPath(
+			filepath.Join(cwd, "./testdata/formats"),
+			"github.com/mariotoffia/go-openapi/generator/testdata/formats",
+		).
+		UseTypeMapping("number", "decimal", gentypes.GoType{
+			Name: "decimal.Decimal", Import: "github.com/shopspring/decimal",
+		}).
+		ToGenerator()
+
+	ctx := generator.GeneratorContext{}
+	err := gen.Generate(&ctx)
+	assert.Equal(t, nil, err)
+
+	// The decimal format is mapped, hence no precision is lost
+	assert.Empty(t, ctx.GetDiagnostics())
+
+	formats := ctx.ResolveTypeDefinition(ctx.GetSpecification().Components["Formats"].Reference)
+	if !assert.NotNil(t, formats) {
+		return
+	}
+
+	types := map[string]gentypes.GoType{}
+	for _, property := range formats.Properties {
+		if assert.NotNil(t, property.Definition.GoType, property.PropertyName) {
+			types[property.PropertyName] = *property.Definition.GoType
+		}
+	}
+
+	assert.Equal(t, gentypes.GoType{Name: "time.Time", Import: "time"}, types["created"])
+	assert.Equal(t, gentypes.GoType{Name: "types.Date", Import: generator.RuntimePackage}, types["birthday"])
+	assert.Equal(t, gentypes.GoType{Name: "types.UUID", Import: generator.RuntimePackage}, types["id"])
+	assert.Equal(t, gentypes.GoType{Name: "[]byte"}, types["payload"])
+	assert.Equal(t, gentypes.GoType{Name: "io.Reader", Import: "io"}, types["file"])
+	assert.Equal(t, gentypes.GoType{Name: "string"}, types["homepage"])
+	assert.Equal(t, gentypes.GoType{Name: "int32"}, types["small"])
+	assert.Equal(t, gentypes.GoType{Name: "int64"}, types["big"])
+	assert.Equal(t, gentypes.GoType{Name: "int"}, types["count"])
+	assert.Equal(t, gentypes.GoType{Name: "float32"}, types["ratio"])
+	assert.Equal(t, gentypes.GoType{Name: "float64"}, types["precise"])
+	assert.Equal(t, gentypes.GoType{Name: "bool"}, types["enabled"])
+
+	// Project wide override
+	assert.Equal(t, gentypes.GoType{
+		Name: "decimal.Decimal", Import: "github.com/shopspring/decimal",
+	}, types["amount"])
+
+	// Property override
+	assert.Equal(t, gentypes.GoType{
+		Name: "money.Amount", Import: "github.com/mariotoffia/money",
+	}, types["price"])
+
+	timestamp := ctx.ResolveTypeDefinition(ctx.GetSpecification().Components["Timestamp"].Reference)
+	if assert.NotNil(t, timestamp) {
+		assert.Equal(t, &gentypes.GoType{Name: "time.Time", Import: "time"}, timestamp.GoType)
+	}
+}
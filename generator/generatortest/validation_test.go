@@ -0,0 +1,56 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationShallRenderMultipleOfAndEnums(t *testing.T) {
+	_, files := generate(t, "validation", nil, ".:validation/*.yaml")
+
+	source := files["validation/validation.go"]
+
+	assert.Contains(t, source, "SeparatorAB  Separator = \"a-b\"")
+	assert.Contains(t, source, "SeparatorAB2 Separator = \"a_b\"")
+	assert.Contains(t, source, "SeparatorAB3 Separator = \"a b\"")
+
+	goTest(t, "validation", "validation", "validation_test.go", `package validation
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	for _, price := range []Price{0, 0.3, 0.7, 19.9, -2.1} {
+		if err := price.Validate(); err != nil {
+			t.Errorf("%v: %s", price, err)
+		}
+	}
+
+	if err := Price(0.35).Validate(); err == nil {
+		t.Error("0.35 is not a multiple of 0.1")
+	}
+
+	if err := Quantity(15).Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if err := Quantity(7).Validate(); err == nil {
+		t.Error("7 is not a multiple of 5")
+	}
+
+	for _, separator := range []Separator{SeparatorAB, SeparatorAB2, SeparatorAB3} {
+		if err := separator.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if err := Separator("ab").Validate(); err == nil {
+		t.Error("ab is not a separator")
+	}
+
+	if err := Nothing("").Validate(); err != nil {
+		t.Error(err)
+	}
+}
+`)
+}
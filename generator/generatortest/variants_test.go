@@ -0,0 +1,137 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestResponseVariants(t *testing.T) {
+	_, files := generate(t, "variants", func(settings *generator.Settings) {
+		settings.UseRequestResponseVariants(true)
+	}, ".:variants/*.yaml")
+
+	source := files["variants/variants.go"]
+
+	assert.Contains(t, source, "type UserRequest struct")
+	assert.Contains(t, source, "type UserResponse struct")
+	assert.Contains(t, source, "Address *AddressRequest `json:\"address,omitempty\"`")
+	assert.Contains(t, source, "type ProfileRequest struct")
+	assert.NotContains(t, source, "PlainRequest")
+
+	goTest(t, "variants", "variants", "variants_test.go", `package variants
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	id, password, street, verified := "u1", "secret123", "Main", true
+
+	user := User{
+		ID:       &id,
+		Password: &password,
+		Name:     "alice",
+		Address:  &Address{Street: &street, Verified: &verified},
+	}
+
+	request, err := json.Marshal(user.ToRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(request) != `+"`"+`{"address":{"street":"Main"},"name":"alice","password":"secret123"}`+"`"+` {
+		t.Fatalf("unexpected request: %s", string(request))
+	}
+
+	response, err := json.Marshal(user.ToResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(response) != `+"`"+`{"address":{"street":"Main","verified":true},"id":"u1","name":"alice"}`+"`"+` {
+		t.Fatalf("unexpected response: %s", string(response))
+	}
+
+	back := user.ToRequest().ToUser()
+	if back.ID != nil || *back.Password != password || *back.Address.Street != street {
+		t.Fatalf("unexpected conversion: %+v", back)
+	}
+
+	var profile Profile
+	profile.Name = "bob"
+	profile.ID = &id
+
+	if profile.ToRequest().Name != "bob" || profile.ToResponse().ID == nil {
+		t.Fatal("expected the composed properties to be converted")
+	}
+
+	short := "short"
+	if err := (UserRequest{Name: "alice", Password: &short}).Validate(); err == nil {
+		t.Fatal("expected the request to be validated")
+	}
+}
+`)
+}
+
+func TestVariantsOfArraysAndMaps(t *testing.T) {
+	_, files := generate(t, "variantcollections", func(settings *generator.Settings) {
+		settings.UseRequestResponseVariants(true)
+	}, ".:variants/*.yaml")
+
+	source := files["variants/variants.go"]
+
+	assert.Contains(t, source, "History []TeamHistoryItemRequest  `json:\"history,omitempty\"`")
+	assert.Contains(t, source, "AdditionalProperties map[string]AddressRequest `json:\"-\"`")
+
+	goTest(t, "variantcollections", "variants", "variants_test.go", `package variants
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCollections(t *testing.T) {
+	at, note, street, verified := "noon", "moved", "Main", true
+	address := Address{Street: &street, Verified: &verified}
+
+	team := Team{
+		History:              []TeamHistoryItem{{At: &at, Note: &note}},
+		Grid:                 [][]Address{{address}},
+		Offices:              map[string]Address{"hq": address},
+		AdditionalProperties: map[string]Address{"home": address},
+	}
+
+	request, err := json.Marshal(team.ToRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `+"`"+`{"grid":[[{"street":"Main"}]],"history":[{"note":"moved"}],"home":{"street":"Main"},"offices":{"hq":{"street":"Main"}}}`+"`"+`
+	if string(request) != expected {
+		t.Fatalf("unexpected request: %s", string(request))
+	}
+
+	response, err := json.Marshal(team.ToResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(response) != string(data) {
+		t.Fatalf("expected %s got %s", string(data), string(response))
+	}
+
+	back := team.ToRequest().ToTeam()
+	if back.History[0].At != nil || *back.History[0].Note != note || *back.Grid[0][0].Street != street {
+		t.Fatalf("unexpected conversion: %+v", back)
+	}
+}
+`)
+}
@@ -0,0 +1,113 @@
+package generatortest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVisitor renames _Order_, strips the deprecated properties, adds a `db` tag and a file
+// that lists the visited compositions and discriminators.
+type testVisitor struct {
+	generator.NopVisitor
+	visited []string
+}
+
+func (v *testVisitor) OnComponent(ctx *generator.GeneratorContext, td *gentypes.TypeDefinition) error {
+	if !td.Inline && td.ID.TypeName == "Order" {
+		generator.SetExtension(td.Schema, generator.ExtensionGoName, "PurchaseOrder")
+	}
+
+	return nil
+}
+
+func (v *testVisitor) OnProperty(ctx *generator.GeneratorContext, td *gentypes.TypeDefinition, property *gentypes.Property) (bool, error) {
+	return property.Definition == nil || !property.Definition.Schema.Deprecated, nil
+}
+
+func (v *testVisitor) OnComposition(ctx *generator.GeneratorContext, td *gentypes.TypeDefinition, composition *gentypes.Composition) error {
+	v.visited = append(v.visited, fmt.Sprintf("%s allOf %s", td.ID.TypeName, composition.Reference.TypeName))
+	return nil
+}
+
+func (v *testVisitor) OnDiscriminator(ctx *generator.GeneratorContext, td *gentypes.TypeDefinition, dc *gentypes.DiscriminatorComponent) error {
+	v.visited = append(v.visited, fmt.Sprintf("%s %s=%s", td.ID.TypeName, dc.Discriminator, dc.ID.TypeName))
+	return nil
+}
+
+func (v *testVisitor) BeforeRender(ctx *generator.GeneratorContext, file *generator.GoFile, decl *generator.GoTypeDecl) error {
+	for _, field := range decl.Fields {
+		if field.Property != nil {
+			field.Tag += fmt.Sprintf(` db:"%s"`, field.Property.PropertyName)
+		}
+	}
+
+	return nil
+}
+
+func (v *testVisitor) AfterRender(ctx *generator.GeneratorContext, files []generator.GeneratedFile) ([]generator.GeneratedFile, error) {
+	return append(files, generator.GeneratedFile{
+		Path:    "visited.txt",
+		Content: []byte(strings.Join(v.visited, "\n")),
+	}), nil
+}
+
+func TestVisitorShallCustomizeGeneratedCode(t *testing.T) {
+	visitor := &testVisitor{}
+
+	_, files := generate(t, "visitor", func(settings *generator.Settings) {
+		settings.UseVisitors(visitor)
+	}, ".:docs/*.yaml")
+
+	source := files["docs/docs.go"]
+
+	assert.Contains(t, source, "type PurchaseOrder struct {")
+	assert.Contains(t, source, "func NewPurchaseOrder(id string) PurchaseOrder {")
+	assert.Contains(t, source, "\tID string `json:\"id\" db:\"id\"`\n")
+	assert.NotContains(t, source, "LegacyCode")
+
+	vet(t, "visitor")
+}
+
+func TestVisitorShallVisitCompositionsAndDiscriminators(t *testing.T) {
+	visitor := &testVisitor{}
+
+	_, files := generate(t, "visitorgraph", func(settings *generator.Settings) {
+		settings.UseVisitors(visitor)
+	}, ".:{anyof,allof}/*.yaml")
+
+	assert.Equal(t, strings.Join([]string{
+		"ImportReport allOf Report",
+		"ImportReport allOf ImportReportBody",
+		"ChooseReporter type=ImportReport",
+		"ChooseReporter type=UsageReport",
+		"UsageReport allOf Report",
+		"UsageReport allOf UsageReportBody",
+	}, "\n"), files["visited.txt"])
+}
+
+// failingVisitor fails on the first component.
+type failingVisitor struct {
+	generator.NopVisitor
+}
+
+func (failingVisitor) OnComponent(ctx *generator.GeneratorContext, td *gentypes.TypeDefinition) error {
+	return fmt.Errorf("no naming rule for: %s", td.ID.TypeName)
+}
+
+func TestVisitorErrorShallFailGeneration(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	settings := generator.NewSettings(generator.Templates{}).
+		UseSpec(filepath.Join(cwd, "testdata", "cycles", "api.yaml"), outputPackage+"/visitorerror").
+		UseVisitors(failingVisitor{})
+
+	err := settings.ToGenerator().Generate(&generator.GeneratorContext{})
+	assert.EqualError(t, err, "no naming rule for: Company")
+}
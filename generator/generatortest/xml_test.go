@@ -0,0 +1,77 @@
+package generatortest
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/go-openapi/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXMLTags(t *testing.T) {
+	_, files := generate(t, "xml", func(settings *generator.Settings) {
+		settings.UseXML(true)
+	}, ".:xml/*.yaml")
+
+	source := files["xml/xml.go"]
+
+	assert.Contains(t, source, "XMLName   xml2.Name         `json:\"-\" xml:\"http://example.com/schema/pet pet\"`")
+	assert.Contains(t, source, "Category  *Category         `json:\"category,omitempty\" xml:\"Category,omitempty\"`")
+	assert.Contains(t, source, "ID        int               `json:\"id\" xml:\"id,attr\"`")
+	assert.Contains(t, source, "PhotoUrls []string          `json:\"photoUrls,omitempty\" xml:\"photoUrls>photoUrl,omitempty\"`")
+	assert.Contains(t, source, "Labels    map[string]string `json:\"labels,omitempty\" xml:\"-\"`")
+
+	goTest(t, "xml", "xml", "xml_test.go", `package xml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestXMLRoundTrip(t *testing.T) {
+	category := "dogs"
+
+	pet := Pet{
+		ID:        7,
+		Name:      "Rex",
+		PhotoUrls: []string{"a.png", "b.png"},
+		Tags:      []string{"good", "boy"},
+		Category:  &Category{Name: &category},
+	}
+
+	data, err := xml.Marshal(pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<pet xmlns=\"http://example.com/schema/pet\" id=\"7\"><Category><name>dogs</name></Category>" +
+		"<petName>Rex</petName><photoUrls><photoUrl>a.png</photoUrl><photoUrl>b.png</photoUrl></photoUrls>" +
+		"<tag>good</tag><tag>boy</tag></pet>"
+
+	if string(data) != expected {
+		t.Fatalf("unexpected xml: %s", string(data))
+	}
+
+	var back Pet
+	if err := xml.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	back.XMLName = xml.Name{}
+	back.Category.XMLName = xml.Name{}
+
+	if !reflect.DeepEqual(back, pet) {
+		t.Fatalf("expected the xml to round trip: %+v", back)
+	}
+
+	if data, err = json.Marshal(pet); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `+"`"+`{"category":{"name":"dogs"},"id":7,"name":"Rex","photoUrls":["a.png","b.png"],"tags":["good","boy"]}`+"`"+` {
+		t.Fatalf("expected the json to be unaffected: %s", string(data))
+	}
+}
+`)
+}
@@ -154,11 +154,7 @@ func (tr *ComponentReference) Equal(other *ComponentReference) bool {
 		return true
 	}
 
-	if tr != nil || other == nil {
-		return false
-	}
-
-	if tr == nil || other != nil {
+	if tr == nil || other == nil {
 		return false
 	}
 
@@ -46,3 +46,17 @@ func TestResolveRelativeReferenceFromTypeAboveRootPathShallFail(t *testing.T) {
 	})
 
 }
+
+func TestEqualComparesNonNilReferences(t *testing.T) {
+	a := NewComponentReference("Report", "report", "generator/testdata/allof", GoModFqPath())
+	b := NewComponentReference("Report", "report", "generator/testdata/allof", GoModFqPath())
+	c := NewComponentReference("Usage", "report", "generator/testdata/allof", GoModFqPath())
+
+	var none *ComponentReference
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.Equal(c))
+	assert.False(t, a.Equal(none))
+	assert.False(t, none.Equal(a))
+	assert.True(t, none.Equal(nil))
+}
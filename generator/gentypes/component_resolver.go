@@ -1,5 +1,29 @@
 package gentypes
 
+import "sort"
+
+// ReferenceResolver keeps track of all components, named and inline, keyed on the
+// `ComponentReference.String()` of their id.
+//
+// A custom resolver, e.g. backed by a persistent cache, shared across multiple specifications
+// or instrumented for debugging, is supplied using `Settings.UseReferenceResolver`. Embed a
+// `ReferenceResolverImpl` to only override some of the methods.
+type ReferenceResolver interface {
+	// RegisterComponent registers the component under its id. An already registered component
+	// with the same id is replaced.
+	RegisterComponent(cd *ComponentDefinition)
+	// ResolveComponent returns the component registered under _ref_ or `nil` if not found.
+	ResolveComponent(ref *ComponentReference) *ComponentDefinition
+	// Components returns all registered components keyed on the string of their id.
+	//
+	// CAUTION: The returned map must not be modified.
+	Components() map[string]*ComponentDefinition
+	// ReferencesTo returns the components whose definition references _ref_, see
+	// `TypeDefinition.References`, sorted on id.
+	ReferencesTo(ref *ComponentReference) []*ComponentDefinition
+}
+
+// ReferenceResolverImpl is the default, in memory, `ReferenceResolver`.
 type ReferenceResolverImpl struct {
 	components map[string]*ComponentDefinition
 }
@@ -21,3 +45,32 @@ func (r *ReferenceResolverImpl) ResolveComponent(ref *ComponentReference) *Compo
 func (r *ReferenceResolverImpl) Components() map[string]*ComponentDefinition {
 	return r.components
 }
+
+func (r *ReferenceResolverImpl) ReferencesTo(ref *ComponentReference) []*ComponentDefinition {
+	id := ref.String()
+	references := []*ComponentDefinition{}
+
+	for _, cd := range r.components {
+		if cd.Definition == nil {
+			continue
+		}
+
+		for _, to := range cd.Definition.References() {
+			target := &to.ID
+			if to.Reference != nil {
+				target = to.Reference
+			}
+
+			if target.String() == id {
+				references = append(references, cd)
+				break
+			}
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].ID.String() < references[j].ID.String()
+	})
+
+	return references
+}
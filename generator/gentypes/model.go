@@ -0,0 +1,583 @@
+package gentypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelVersion is the version of the serialized model, see `MarshalModel`. It is increased
+// when the serialization changes in a way that is not backwards compatible.
+const ModelVersion = "1"
+
+// ModelFormat is the encoding of a serialized model.
+type ModelFormat string
+
+const (
+	// ModelFormatJSON encodes the model as indented json.
+	ModelFormatJSON ModelFormat = "json"
+	// ModelFormatYAML encodes the model as yaml.
+	ModelFormatYAML ModelFormat = "yaml"
+)
+
+// Model is the resolved model of an _OpenAPI_ specification, as processed by the generator,
+// that can be serialized using `MarshalModel` and read back using `UnmarshalModel`.
+type Model struct {
+	// Version is the version of the serialization the model was read from, or is written
+	// as, see `ModelVersion`.
+	Version string
+	// Specification is the named components of the specification.
+	Specification OpenAPISpecificationDefinition
+	// Components are all components, named and inline, keyed by `ComponentReference.String()`
+	// such that references can be resolved, see `ResolveDefinition`.
+	Components map[string]*ComponentDefinition
+}
+
+// ResolveComponent returns the component with the identity _ref_ or `nil` when not found.
+func (m *Model) ResolveComponent(ref *ComponentReference) *ComponentDefinition {
+	return m.Components[ref.String()]
+}
+
+// ResolveDefinition returns the definition of the _component_, either directly or by
+// following its references.
+func (m *Model) ResolveDefinition(component *ComponentDefinition) *TypeDefinition {
+	for component != nil {
+		if component.Definition != nil {
+			return component.Definition
+		}
+
+		if component.Reference == nil {
+			return nil
+		}
+
+		component = m.ResolveComponent(component.Reference)
+	}
+
+	return nil
+}
+
+// referenceModel is the serialized `ComponentReference`.
+type referenceModel struct {
+	TypeName  string `json:"typeName" yaml:"typeName"`
+	NameSpace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Module    string `json:"module" yaml:"module"`
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"`
+	RootPath  string `json:"rootPath" yaml:"rootPath"`
+}
+
+// componentModel is the serialized `ComponentDefinition`. The definition is the identity of
+// a type in `wireModel.Types`.
+type componentModel struct {
+	ID         referenceModel  `json:"id" yaml:"id"`
+	Reference  *referenceModel `json:"reference,omitempty" yaml:"reference,omitempty"`
+	Definition *referenceModel `json:"definition,omitempty" yaml:"definition,omitempty"`
+}
+
+type propertyModel struct {
+	componentModel `yaml:",inline"`
+	Name           string `json:"name" yaml:"name"`
+	Required       bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Pointer        bool   `json:"pointer,omitempty" yaml:"pointer,omitempty"`
+}
+
+type compositionModel struct {
+	componentModel `yaml:",inline"`
+	Inline         bool `json:"inline,omitempty" yaml:"inline,omitempty"`
+}
+
+type discriminatorModel struct {
+	componentModel `yaml:",inline"`
+	Discriminator  string `json:"discriminator" yaml:"discriminator"`
+	MapFrom        string `json:"mapFrom,omitempty" yaml:"mapFrom,omitempty"`
+}
+
+type goTypeModel struct {
+	Name   string `json:"name" yaml:"name"`
+	Import string `json:"import,omitempty" yaml:"import,omitempty"`
+}
+
+// typeModel is the serialized `TypeDefinition`. The _kind_ is informational and ignored when
+// read.
+type typeModel struct {
+	ID                      referenceModel       `json:"id" yaml:"id"`
+	Kind                    string               `json:"kind" yaml:"kind"`
+	GoPackage               string               `json:"goPackage" yaml:"goPackage"`
+	GoType                  *goTypeModel         `json:"goType,omitempty" yaml:"goType,omitempty"`
+	Inline                  bool                 `json:"inline,omitempty" yaml:"inline,omitempty"`
+	Schema                  map[string]any       `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Properties              []propertyModel      `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Composition             []compositionModel   `json:"composition,omitempty" yaml:"composition,omitempty"`
+	DiscriminatorComponents []discriminatorModel `json:"discriminatorComponents,omitempty" yaml:"discriminatorComponents,omitempty"`
+	OneOf                   []componentModel     `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf                   []componentModel     `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Items                   *componentModel      `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties    *componentModel      `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Not                     *componentModel      `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// wireModel is the serialized `Model`.
+type wireModel struct {
+	Version    string                    `json:"version" yaml:"version"`
+	Names      map[string]componentModel `json:"names" yaml:"names"`
+	Components []componentModel          `json:"components" yaml:"components"`
+	Types      []typeModel               `json:"types" yaml:"types"`
+}
+
+// MarshalModel serializes the _model_ in the _format_. The serialization is stable, i.e. the
+// same model always renders the same output, and has the following layout:
+//
+//	version: "1"
+//	names:        # The named components of the specification
+//	  Report: {id: <id>, reference: <id>, definition: <id>}
+//	components:   # All components sorted on id
+//	  - id: <id>
+//	    reference: <id>   # When a reference to another component
+//	    definition: <id>  # When defined, the id of the type
+//	types:        # All type definitions sorted on id
+//	  - id: <id>
+//	    kind: object      # object, array, map, union, enum, primitive or any
+//	    goPackage: github.com/org/models/reports
+//	    goType: {name: string, import: ""}
+//	    inline: false
+//	    schema: {...}     # The schema as in the specification
+//	    properties: [{name: type, required: true, pointer: false, id: <id>, ...}]
+//	    composition: [{inline: false, id: <id>, ...}]
+//	    discriminatorComponents: [{discriminator: type, mapFrom: usage, id: <id>, ...}]
+//	    oneOf: [...]
+//	    anyOf: [...]
+//	    items: {...}
+//	    additionalProperties: {...}
+//	    not: {...}
+//
+// An _<id>_ is a `ComponentReference` as _{typeName, namespace, module, path, rootPath}_.
+// Empty values are omitted.
+func MarshalModel(model *Model, format ModelFormat) ([]byte, error) {
+	wire := wireModel{
+		Version:    ModelVersion,
+		Names:      map[string]componentModel{},
+		Components: []componentModel{},
+		Types:      []typeModel{},
+	}
+
+	types := map[string]*TypeDefinition{}
+	pending := []*TypeDefinition{}
+
+	add := func(component *ComponentDefinition) {
+		if component != nil && component.Definition != nil {
+			if _, ok := types[component.Definition.ID.String()]; !ok {
+				types[component.Definition.ID.String()] = component.Definition
+				pending = append(pending, component.Definition)
+			}
+		}
+	}
+
+	for name, component := range model.Specification.Components {
+		wire.Names[name] = toComponentModel(component)
+		add(component)
+	}
+
+	for _, component := range model.Components {
+		wire.Components = append(wire.Components, toComponentModel(component))
+		add(component)
+	}
+
+	// Inline types that are only declared within other types
+	for len(pending) > 0 {
+		td := pending[0]
+		pending = pending[1:]
+
+		for i := range td.Properties {
+			add(&td.Properties[i].ComponentDefinition)
+		}
+
+		for i := range td.Composition {
+			add(&td.Composition[i].ComponentDefinition)
+		}
+
+		for i := range td.DiscriminatorComponents {
+			add(&td.DiscriminatorComponents[i].ComponentDefinition)
+		}
+
+		for i := range td.OneOf {
+			add(&td.OneOf[i])
+		}
+
+		for i := range td.AnyOf {
+			add(&td.AnyOf[i])
+		}
+
+		add(td.Items)
+		add(td.AdditionalProperties)
+		add(td.Not)
+	}
+
+	sort.Slice(wire.Components, func(i, j int) bool {
+		return referenceKey(wire.Components[i].ID) < referenceKey(wire.Components[j].ID)
+	})
+
+	ids := make([]string, 0, len(types))
+	for id := range types {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		tm, err := toTypeModel(types[id])
+		if err != nil {
+			return nil, err
+		}
+
+		wire.Types = append(wire.Types, tm)
+	}
+
+	switch format {
+	case ModelFormatJSON:
+		data, err := json.MarshalIndent(wire, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	case ModelFormatYAML:
+		return yaml.Marshal(wire)
+	}
+
+	return nil, fmt.Errorf("unknown model format: %s", format)
+}
+
+// UnmarshalModel reads a model serialized by `MarshalModel` in the _format_.
+//
+// NOTE: The `$ref` within a `TypeDefinition.Schema` are not resolved, use the type definitions
+// of the components instead.
+func UnmarshalModel(data []byte, format ModelFormat) (*Model, error) {
+	var wire wireModel
+
+	switch format {
+	case ModelFormatJSON:
+		if err := json.Unmarshal(data, &wire); err != nil {
+			return nil, err
+		}
+	case ModelFormatYAML:
+		if err := yaml.Unmarshal(data, &wire); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown model format: %s", format)
+	}
+
+	if wire.Version != ModelVersion {
+		return nil, fmt.Errorf("unsupported model version: %s, expected: %s", wire.Version, ModelVersion)
+	}
+
+	model := &Model{
+		Version:       wire.Version,
+		Specification: OpenAPISpecificationDefinition{Components: map[string]*ComponentDefinition{}},
+		Components:    map[string]*ComponentDefinition{},
+	}
+
+	types := map[string]*TypeDefinition{}
+	for i := range wire.Types {
+		td, err := fromTypeModel(&wire.Types[i])
+		if err != nil {
+			return nil, err
+		}
+
+		types[td.ID.String()] = td
+	}
+
+	resolve := func(cm *componentModel) (ComponentDefinition, error) {
+		component := ComponentDefinition{ID: fromReferenceModel(cm.ID)}
+
+		if cm.Reference != nil {
+			ref := fromReferenceModel(*cm.Reference)
+			component.Reference = &ref
+		}
+
+		if cm.Definition != nil {
+			ref := fromReferenceModel(*cm.Definition)
+			if component.Definition = types[ref.String()]; component.Definition == nil {
+				return component, fmt.Errorf("type: %s of component: %s not found", ref.String(), component.ID.String())
+			}
+		}
+
+		return component, nil
+	}
+
+	for i := range wire.Components {
+		component, err := resolve(&wire.Components[i])
+		if err != nil {
+			return nil, err
+		}
+
+		model.Components[component.ID.String()] = &component
+	}
+
+	for name, cm := range wire.Names {
+		component, err := resolve(&cm)
+		if err != nil {
+			return nil, err
+		}
+
+		model.Specification.Components[name] = &component
+	}
+
+	for i := range wire.Types {
+		if err := linkTypeModel(&wire.Types[i], types, resolve); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+func toReferenceModel(ref ComponentReference) referenceModel {
+	return referenceModel(ref)
+}
+
+func fromReferenceModel(ref referenceModel) ComponentReference {
+	return ComponentReference(ref)
+}
+
+func referenceKey(ref referenceModel) string {
+	id := fromReferenceModel(ref)
+	return id.String()
+}
+
+func toComponentModel(component *ComponentDefinition) componentModel {
+	cm := componentModel{ID: toReferenceModel(component.ID)}
+
+	if component.Reference != nil {
+		ref := toReferenceModel(*component.Reference)
+		cm.Reference = &ref
+	}
+
+	if component.Definition != nil {
+		ref := toReferenceModel(component.Definition.ID)
+		cm.Definition = &ref
+	}
+
+	return cm
+}
+
+func toTypeModel(td *TypeDefinition) (typeModel, error) {
+	tm := typeModel{
+		ID:        toReferenceModel(td.ID),
+		Kind:      typeKind(td),
+		GoPackage: td.GoPackage,
+		Inline:    td.Inline,
+	}
+
+	if td.GoType != nil {
+		tm.GoType = &goTypeModel{Name: td.GoType.Name, Import: td.GoType.Import}
+	}
+
+	if td.Schema != nil {
+		data, err := json.Marshal(td.Schema)
+		if err != nil {
+			return tm, fmt.Errorf("schema of: %s can not be marshalled: %s", td.ID.String(), err.Error())
+		}
+
+		if err := json.Unmarshal(data, &tm.Schema); err != nil {
+			return tm, err
+		}
+	}
+
+	for i := range td.Properties {
+		property := &td.Properties[i]
+		tm.Properties = append(tm.Properties, propertyModel{
+			componentModel: toComponentModel(&property.ComponentDefinition),
+			Name:           property.PropertyName,
+			Required:       property.Required,
+			Pointer:        property.Pointer,
+		})
+	}
+
+	for i := range td.Composition {
+		composition := &td.Composition[i]
+		tm.Composition = append(tm.Composition, compositionModel{
+			componentModel: toComponentModel(&composition.ComponentDefinition),
+			Inline:         composition.Inline,
+		})
+	}
+
+	for i := range td.DiscriminatorComponents {
+		dc := &td.DiscriminatorComponents[i]
+		tm.DiscriminatorComponents = append(tm.DiscriminatorComponents, discriminatorModel{
+			componentModel: toComponentModel(&dc.ComponentDefinition),
+			Discriminator:  dc.Discriminator,
+			MapFrom:        dc.MapFrom,
+		})
+	}
+
+	for i := range td.OneOf {
+		tm.OneOf = append(tm.OneOf, toComponentModel(&td.OneOf[i]))
+	}
+
+	for i := range td.AnyOf {
+		tm.AnyOf = append(tm.AnyOf, toComponentModel(&td.AnyOf[i]))
+	}
+
+	optional := func(component *ComponentDefinition) *componentModel {
+		if component == nil {
+			return nil
+		}
+
+		cm := toComponentModel(component)
+		return &cm
+	}
+
+	tm.Items = optional(td.Items)
+	tm.AdditionalProperties = optional(td.AdditionalProperties)
+	tm.Not = optional(td.Not)
+
+	return tm, nil
+}
+
+// fromTypeModel creates the `TypeDefinition` of _tm_ without its components, these are
+// linked when all types are created, see `linkTypeModel`.
+func fromTypeModel(tm *typeModel) (*TypeDefinition, error) {
+	td := &TypeDefinition{
+		ID:          fromReferenceModel(tm.ID),
+		GoPackage:   tm.GoPackage,
+		Inline:      tm.Inline,
+		Properties:  []Property{},
+		Composition: []Composition{},
+	}
+
+	if tm.GoType != nil {
+		td.GoType = &GoType{Name: tm.GoType.Name, Import: tm.GoType.Import}
+	}
+
+	if tm.Schema != nil {
+		data, err := json.Marshal(tm.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		td.Schema = &openapi3.Schema{}
+		if err := json.Unmarshal(data, td.Schema); err != nil {
+			return nil, fmt.Errorf("schema of: %s is not valid: %s", td.ID.String(), err.Error())
+		}
+	}
+
+	return td, nil
+}
+
+func linkTypeModel(
+	tm *typeModel,
+	types map[string]*TypeDefinition,
+	resolve func(cm *componentModel) (ComponentDefinition, error)) error {
+
+	id := fromReferenceModel(tm.ID)
+	td := types[id.String()]
+
+	for i := range tm.Properties {
+		component, err := resolve(&tm.Properties[i].componentModel)
+		if err != nil {
+			return err
+		}
+
+		td.Properties = append(td.Properties, Property{
+			ComponentDefinition: component,
+			Required:            tm.Properties[i].Required,
+			PropertyName:        tm.Properties[i].Name,
+			Pointer:             tm.Properties[i].Pointer,
+		})
+	}
+
+	for i := range tm.Composition {
+		component, err := resolve(&tm.Composition[i].componentModel)
+		if err != nil {
+			return err
+		}
+
+		td.Composition = append(td.Composition, Composition{
+			ComponentDefinition: component,
+			Inline:              tm.Composition[i].Inline,
+		})
+	}
+
+	for i := range tm.DiscriminatorComponents {
+		component, err := resolve(&tm.DiscriminatorComponents[i].componentModel)
+		if err != nil {
+			return err
+		}
+
+		td.DiscriminatorComponents = append(td.DiscriminatorComponents, DiscriminatorComponent{
+			ComponentDefinition: component,
+			Discriminator:       tm.DiscriminatorComponents[i].Discriminator,
+			MapFrom:             tm.DiscriminatorComponents[i].MapFrom,
+		})
+	}
+
+	components := func(cms []componentModel) ([]ComponentDefinition, error) {
+		if cms == nil {
+			return nil, nil
+		}
+
+		result := make([]ComponentDefinition, 0, len(cms))
+		for i := range cms {
+			component, err := resolve(&cms[i])
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, component)
+		}
+
+		return result, nil
+	}
+
+	var err error
+	if td.OneOf, err = components(tm.OneOf); err != nil {
+		return err
+	}
+
+	if td.AnyOf, err = components(tm.AnyOf); err != nil {
+		return err
+	}
+
+	optional := func(cm *componentModel) (*ComponentDefinition, error) {
+		if cm == nil {
+			return nil, nil
+		}
+
+		component, err := resolve(cm)
+		return &component, err
+	}
+
+	if td.Items, err = optional(tm.Items); err != nil {
+		return err
+	}
+
+	if td.AdditionalProperties, err = optional(tm.AdditionalProperties); err != nil {
+		return err
+	}
+
+	td.Not, err = optional(tm.Not)
+	return err
+}
+
+// typeKind returns the informational kind of _td_.
+func typeKind(td *TypeDefinition) string {
+	switch {
+	case td.IsUnion():
+		return "union"
+	case td.IsArray():
+		return "array"
+	case td.IsMap():
+		return "map"
+	case td.IsEnum():
+		return "enum"
+	case td.IsPrimitive():
+		return "primitive"
+	case td.Schema != nil && td.IsObject():
+		return "object"
+	}
+
+	return "any"
+}
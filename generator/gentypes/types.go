@@ -33,6 +33,100 @@ type TypeDefinition struct {
 	// it represents. This is based on _oneOf_, _discriminator_ and _mapping_ keywords in
 	// a _OpenAPI_ specification.
 	DiscriminatorComponents []DiscriminatorComponent
+	// OneOf are the types where exactly one must match when there is no discriminator.
+	// This is the _OpenAPI_ `oneOf` keyword.
+	OneOf []ComponentDefinition
+	// AnyOf are the types where at least one must match. This is the _OpenAPI_ `anyOf`
+	// keyword.
+	AnyOf []ComponentDefinition
+	// GoType is the go type that a primitive `TypeDefinition` maps to. It is resolved
+	// from the _type_ and _format_ keywords using the type mapping in the settings. It
+	// is `nil` when not a primitive such as objects and arrays.
+	GoType *GoType
+	// Items is the component of the array items when this is an array. This is
+	// the _OpenAPI_ `items` keyword.
+	Items *ComponentDefinition
+	// AdditionalProperties is the component of the values when this is a map or, when
+	// it has properties, the values of any other properties than the declared ones. This
+	// is the _OpenAPI_ `additionalProperties` keyword.
+	AdditionalProperties *ComponentDefinition
+	// Not is the component that a value must not match. This is the _OpenAPI_ `not`
+	// keyword.
+	Not *ComponentDefinition
+	// Inline is set when the type is declared inline in another schema, e.g. as a
+	// property or array items, and hence has no name in the _OpenAPI_ specification.
+	Inline bool
+}
+
+// IsArray returns `true` when the type is an array.
+func (td *TypeDefinition) IsArray() bool {
+	return td.Items != nil
+}
+
+// IsMap returns `true` when the type is a map with no declared properties.
+func (td *TypeDefinition) IsMap() bool {
+	return td.AdditionalProperties != nil && len(td.Properties) == 0 && len(td.Composition) == 0
+}
+
+// IsPrimitive returns `true` when the type maps directly onto a go type such as
+// `string` or `time.Time`.
+func (td *TypeDefinition) IsPrimitive() bool {
+	return td.GoType != nil
+}
+
+// IsEnum returns `true` when the type is a primitive with a set of allowed values.
+func (td *TypeDefinition) IsEnum() bool {
+	return td.IsPrimitive() && len(td.Schema.Enum) > 0
+}
+
+// IsUnion returns `true` when the type is one of several types, either selected by
+// a discriminator or by the _oneOf_ or _anyOf_ keywords.
+func (td *TypeDefinition) IsUnion() bool {
+	return len(td.DiscriminatorComponents) > 0 || len(td.OneOf) > 0 || len(td.AnyOf) > 0
+}
+
+// IsObject returns `true` when the type has properties or compositions, or is
+// declared as an object and is not a map.
+func (td *TypeDefinition) IsObject() bool {
+	if td.IsPrimitive() || td.IsArray() || td.IsMap() || td.IsUnion() {
+		return false
+	}
+
+	return td.Schema.Type == "object" || len(td.Properties) > 0 || len(td.Composition) > 0
+}
+
+// References returns the components that the type references, i.e. the compositions,
+// properties, array items, additional properties, union members and _not_ component.
+func (td *TypeDefinition) References() []*ComponentDefinition {
+	references := []*ComponentDefinition{}
+
+	for i := range td.Composition {
+		references = append(references, &td.Composition[i].ComponentDefinition)
+	}
+
+	for i := range td.Properties {
+		references = append(references, &td.Properties[i].ComponentDefinition)
+	}
+
+	for _, cd := range []*ComponentDefinition{td.Items, td.AdditionalProperties, td.Not} {
+		if cd != nil {
+			references = append(references, cd)
+		}
+	}
+
+	for i := range td.OneOf {
+		references = append(references, &td.OneOf[i])
+	}
+
+	for i := range td.AnyOf {
+		references = append(references, &td.AnyOf[i])
+	}
+
+	for i := range td.DiscriminatorComponents {
+		references = append(references, &td.DiscriminatorComponents[i].ComponentDefinition)
+	}
+
+	return references
 }
 
 type DiscriminatorComponent struct {
@@ -55,4 +149,16 @@ type Property struct {
 	Required bool
 	// PropertyName is the name of the property
 	PropertyName string
+	// Pointer is set when the property must be a pointer even though it is
+	// required, e.g. to break a reference cycle.
+	Pointer bool
+}
+
+// GoType is the go type that a _OpenAPI_ type and format is mapped to.
+type GoType struct {
+	// Name is the go type name, e.g. `time.Time` or `[]byte`.
+	Name string
+	// Import is the go package that needs to be imported in order to
+	// use the type. It is empty when no import is needed.
+	Import string
 }
@@ -1,9 +1,16 @@
 package generator
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/types"
 )
 
 // AddSchemaToObject will add a schema to an object schema.
@@ -114,51 +121,129 @@ func ExtendSchema(
 	return &target, nil
 }
 
+// ExtensionGoPatterns holds the patterns, in addition to _pattern_, that a string must
+// match. It is set when _allOf_ members with different patterns are merged.
+const ExtensionGoPatterns = "x-go-patterns"
+
+// SchemaPatterns returns the _pattern_ of the _schema_ followed by the patterns of the
+// `ExtensionGoPatterns` extension.
+func SchemaPatterns(schema *openapi3.Schema) []string {
+	patterns := []string{}
+	if schema.Pattern != "" {
+		patterns = append(patterns, schema.Pattern)
+	}
+
+	switch v := schema.Extensions[ExtensionGoPatterns].(type) {
+	case []string:
+		patterns = append(patterns, v...)
+	case []any:
+		for i := range v {
+			if s, ok := v[i].(string); ok {
+				patterns = append(patterns, s)
+			}
+		}
+	case json.RawMessage:
+		var additional []string
+		if err := json.Unmarshal(v, &additional); err == nil {
+			patterns = append(patterns, additional...)
+		}
+	}
+
+	return patterns
+}
+
+// UnsatisfiableError is returned when two schemas can not be satisfied together. The
+// schema merged so far is still returned along with it, see `IntersectSchemas`.
+type UnsatisfiableError struct {
+	// Reasons are the conflicts, e.g. _property: id: type string conflicts with type integer_.
+	Reasons []string
+}
+
+func (e *UnsatisfiableError) Error() string {
+	return "allOf members are unsatisfiable: " + strings.Join(e.Reasons, ", ")
+}
+
 // MergeSchemaObjects will merge two schema of type object into one schema.
 //
+// The merge has _allOf_ semantics, i.e. the merged schema is the intersection of both
+// schemas. Hence, the largest minimum and the smallest maximum are kept, enums are
+// intersected and properties declared in both schemas are merged recursively. If the
+// schemas can not be satisfied together, e.g. a property is a _string_ in one and an
+// _integer_ in the other, the merged schema is returned with an `*UnsatisfiableError`.
+//
+// A schema without a type, e.g. an _allOf_ member with only _required_, is merged as an object.
+//
 // CAUTION: It will not merge a object schema with a non-object schema.
 func MergeSchemaObjects(ctx *GeneratorContext, to *openapi3.Schema, from *openapi3.Schema) (*openapi3.Schema, error) {
+	if from.Type != "object" && to.Type != "object" && (from.Type != "" || to.Type != "") {
+		return nil, fmt.Errorf("cannot merge schemas that are not type of object")
+	}
 
+	return IntersectSchemas(ctx, to, from, "")
+}
+
+// IntersectSchemas returns a new schema that is the intersection of _to_ and _from_. Neither
+// _to_ nor _from_ is altered. The _path_ is the property path used in error messages.
+//
+// When both has a _not_, the merged _not_ is the _anyOf_ of both since a value must match
+// neither. When both has a _pattern_, the _pattern_ of _to_ is kept and the other is added to
+// the `ExtensionGoPatterns` extension.
+//
+// If the schemas can not be satisfied together, the constraint of _to_ is kept for each
+// conflict and the merged schema is returned with an `*UnsatisfiableError` that has all
+// conflicts. Any other error returns a `nil` schema.
+func IntersectSchemas(ctx *GeneratorContext, to, from *openapi3.Schema, path string) (*openapi3.Schema, error) {
 	merged := *to
+	reasons := []string{}
 
-	if from.Type != "object" && to.Type != "object" {
-		return nil, fmt.Errorf("cannot merge schemas that are not type of object")
+	unsatisfiable := func(format string, args ...any) {
+		if path == "" {
+			reasons = append(reasons, fmt.Sprintf(format, args...))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("property: %s: %s", path, fmt.Sprintf(format, args...)))
+		}
 	}
 
-	if len(from.OneOf) > 0 {
-		for i := range from.OneOf {
-			if !ContainsSchemaRef(to.OneOf, from.OneOf[i]) {
-				merged.OneOf = append(merged.OneOf, from.OneOf[i])
-			}
-		}
+	var err error
+	if merged.Type, err = intersectTypes(to.Type, from.Type); err != nil {
+		merged.Type = to.Type
+		unsatisfiable(err.Error())
 	}
 
-	if len(from.AllOf) > 0 {
-		for i := range from.AllOf {
-			if !ContainsSchemaRef(to.AllOf, from.AllOf[i]) {
-				merged.AllOf = append(merged.AllOf, from.AllOf[i])
-			}
+	if merged.Format == "" {
+		merged.Format = from.Format
+	}
+
+	merged.OneOf = append(openapi3.SchemaRefs{}, to.OneOf...)
+	for i := range from.OneOf {
+		if !ContainsSchemaRef(to.OneOf, from.OneOf[i]) {
+			merged.OneOf = append(merged.OneOf, from.OneOf[i])
 		}
 	}
 
-	if len(from.AnyOf) > 0 {
-		for i := range from.AnyOf {
-			if !ContainsSchemaRef(to.AnyOf, from.AnyOf[i]) {
-				merged.AnyOf = append(merged.AnyOf, from.AnyOf[i])
-			}
+	merged.AllOf = append(openapi3.SchemaRefs{}, to.AllOf...)
+	for i := range from.AllOf {
+		if !ContainsSchemaRef(to.AllOf, from.AllOf[i]) {
+			merged.AllOf = append(merged.AllOf, from.AllOf[i])
 		}
 	}
 
-	if from.Not != nil {
-		if merged.Not == nil {
-			// Premiere the to Not and drop the from Not
-			merged.Not = from.Not
+	merged.AnyOf = append(openapi3.SchemaRefs{}, to.AnyOf...)
+	for i := range from.AnyOf {
+		if !ContainsSchemaRef(to.AnyOf, from.AnyOf[i]) {
+			merged.AnyOf = append(merged.AnyOf, from.AnyOf[i])
 		}
 	}
 
+	merged.Not = intersectNot(to.Not, from.Not)
+
 	merged.Title = MergeStrings(to.Title, from.Title)
 	merged.Description = MergeStrings(to.Description, from.Description)
-	merged.Enum = append(merged.Enum, from.Enum...)
+
+	if merged.Enum, err = intersectEnums(to.Enum, from.Enum); err != nil {
+		merged.Enum = to.Enum
+		unsatisfiable(err.Error())
+	}
 
 	if to.Default == nil {
 		merged.Default = from.Default
@@ -172,123 +257,338 @@ func MergeSchemaObjects(ctx *GeneratorContext, to *openapi3.Schema, from *openap
 		merged.ExternalDocs = from.ExternalDocs
 	}
 
-	if !to.UniqueItems {
-		merged.UniqueItems = from.UniqueItems
+	merged.UniqueItems = to.UniqueItems || from.UniqueItems
+	merged.Nullable = to.Nullable && from.Nullable
+	merged.ReadOnly = to.ReadOnly || from.ReadOnly
+	merged.WriteOnly = to.WriteOnly || from.WriteOnly
+	merged.AllowEmptyValue = to.AllowEmptyValue || from.AllowEmptyValue
+	merged.Deprecated = to.Deprecated || from.Deprecated
+
+	if merged.ReadOnly && merged.WriteOnly {
+		unsatisfiable("both readOnly and writeOnly")
 	}
 
-	if !to.ExclusiveMin {
-		merged.ExclusiveMin = from.ExclusiveMin
+	if to.XML == nil {
+		merged.XML = from.XML
 	}
 
-	if !to.ExclusiveMax {
-		merged.ExclusiveMax = from.ExclusiveMax
+	merged.Min, merged.ExclusiveMin = intersectBound(to.Min, to.ExclusiveMin, from.Min, from.ExclusiveMin, true)
+	merged.Max, merged.ExclusiveMax = intersectBound(to.Max, to.ExclusiveMax, from.Max, from.ExclusiveMax, false)
+
+	if merged.Min != nil && merged.Max != nil &&
+		(*merged.Min > *merged.Max || (*merged.Min == *merged.Max && (merged.ExclusiveMin || merged.ExclusiveMax))) {
+
+		unsatisfiable("minimum %v is not less than maximum %v", *merged.Min, *merged.Max)
 	}
 
-	if !to.Nullable {
-		merged.Nullable = from.Nullable
+	merged.MultipleOf = intersectMultipleOf(to.MultipleOf, from.MultipleOf)
+
+	intersectPatterns(&merged, to, from)
+
+	merged.MinLength, merged.MaxLength = intersectCount(to.MinLength, to.MaxLength, from.MinLength, from.MaxLength)
+	if merged.MaxLength != nil && merged.MinLength > *merged.MaxLength {
+		unsatisfiable("minLength %d is greater than maxLength %d", merged.MinLength, *merged.MaxLength)
 	}
 
-	if !to.ReadOnly {
-		merged.ReadOnly = from.ReadOnly
+	merged.MinItems, merged.MaxItems = intersectCount(to.MinItems, to.MaxItems, from.MinItems, from.MaxItems)
+	if merged.MaxItems != nil && merged.MinItems > *merged.MaxItems {
+		unsatisfiable("minItems %d is greater than maxItems %d", merged.MinItems, *merged.MaxItems)
 	}
 
-	if !to.WriteOnly {
-		merged.WriteOnly = from.WriteOnly
+	merged.MinProps, merged.MaxProps = intersectCount(to.MinProps, to.MaxProps, from.MinProps, from.MaxProps)
+	if merged.MaxProps != nil && merged.MinProps > *merged.MaxProps {
+		unsatisfiable("minProperties %d is greater than maxProperties %d", merged.MinProps, *merged.MaxProps)
 	}
 
-	if !to.AllowEmptyValue {
-		merged.AllowEmptyValue = from.AllowEmptyValue
+	// nested records the conflicts of a nested intersection, other errors are returned
+	nested := func(err error) error {
+		var conflicts *UnsatisfiableError
+		if errors.As(err, &conflicts) {
+			reasons = append(reasons, conflicts.Reasons...)
+			return nil
+		}
+
+		return err
 	}
 
-	if !to.Deprecated {
-		merged.Deprecated = from.Deprecated
+	merged.Items, err = intersectSchemaRefs(ctx, to.Items, from.Items, path+"[]")
+	if err = nested(err); err != nil {
+		return nil, err
 	}
 
-	if to.XML == nil {
-		merged.XML = from.XML
+	if to.AdditionalPropertiesAllowed == nil && to.AdditionalProperties == nil {
+		merged.AdditionalPropertiesAllowed = from.AdditionalPropertiesAllowed
+		merged.AdditionalProperties = from.AdditionalProperties
 	}
 
-	if to.Min == nil {
-		merged.Min = from.Min
+	if to.Discriminator == nil {
+		merged.Discriminator = from.Discriminator
 	}
 
-	if to.Max == nil {
-		merged.Max = from.Max
+	merged.Required = append([]string{}, to.Required...)
+	for i := range from.Required {
+		if !ContainsString(merged.Required, from.Required[i]) {
+			merged.Required = append(merged.Required, from.Required[i])
+		}
 	}
 
-	if to.MultipleOf == nil {
-		merged.MultipleOf = from.MultipleOf
+	if len(to.Properties) > 0 || len(from.Properties) > 0 {
+		merged.Properties = openapi3.Schemas{}
+
+		for k := range to.Properties {
+			merged.Properties[k] = to.Properties[k]
+		}
+
+		// Sorted such that the conflicts are reported in a stable order
+		names := []string{}
+		for k := range from.Properties {
+			names = append(names, k)
+		}
+
+		sort.Strings(names)
+
+		for _, k := range names {
+			property_path := k
+			if path != "" {
+				property_path = path + "." + k
+			}
+
+			merged.Properties[k], err = intersectSchemaRefs(ctx, to.Properties[k], from.Properties[k], property_path)
+			if err = nested(err); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if to.MinLength == 0 {
-		merged.MinLength = from.MinLength
+	if len(reasons) > 0 {
+		return &merged, &UnsatisfiableError{Reasons: reasons}
 	}
 
-	if to.MaxLength == nil {
-		merged.MaxLength = from.MaxLength
+	return &merged, nil
+}
+
+// intersectSchemaRefs intersects two, possibly `nil`, schema references. Two inline schemas
+// are intersected and two references must be the same. When a reference is intersected
+// with a inline schema that constrains the value, the inline constraints are merged into
+// the referenced schema and the result is a inline schema, otherwise the reference is kept.
+//
+// As `IntersectSchemas`, the merged reference is returned along with an `*UnsatisfiableError`.
+func intersectSchemaRefs(ctx *GeneratorContext, to, from *openapi3.SchemaRef, path string) (*openapi3.SchemaRef, error) {
+	if to == nil || to.Value == nil && to.Ref == "" {
+		return from, nil
 	}
 
-	if to.Pattern == "" {
-		merged.Pattern = from.Pattern
+	if from == nil || from.Value == nil && from.Ref == "" {
+		return to, nil
 	}
 
-	if to.MinItems == 0 {
-		merged.MinItems = from.MinItems
+	unsatisfiable := func(format string, args ...any) error {
+		return &UnsatisfiableError{Reasons: []string{
+			fmt.Sprintf("property: %s: %s", path, fmt.Sprintf(format, args...)),
+		}}
 	}
 
-	if to.MaxItems == nil {
-		merged.MaxItems = from.MaxItems
+	switch {
+	case to.Ref != "" && from.Ref != "":
+		if to.Ref != from.Ref {
+			return to, unsatisfiable("references %s and %s", to.Ref, from.Ref)
+		}
+
+		return to, nil
+	case to.Ref != "" || from.Ref != "":
+		ref, inline := to, from
+		if ref.Ref == "" {
+			ref, inline = from, to
+		}
+
+		if ref.Value == nil || inline.Value == nil {
+			return ref, nil
+		}
+
+		if _, err := intersectTypes(to.Value.Type, from.Value.Type); err != nil {
+			return ref, unsatisfiable(err.Error())
+		}
+
+		if !IsConstrainingSchema(inline.Value) {
+			return ref, nil
+		}
 	}
 
-	if to.Items == nil {
-		merged.Items = from.Items
+	merged, err := IntersectSchemas(ctx, to.Value, from.Value, path)
+	if merged == nil {
+		return nil, err
 	}
 
-	merged.MinProps += from.MinProps
+	return &openapi3.SchemaRef{Value: merged}, err
+}
 
-	if from.MaxProps != nil {
-		if merged.MaxProps == nil {
-			merged.MaxProps = from.MaxProps
+// intersectNot returns a _not_ that excludes the values of both _to_ and _from_, i.e.
+// _not: {anyOf: [to, from]}_. A _not_ that already is such an _anyOf_ is flattened.
+func intersectNot(to, from *openapi3.SchemaRef) *openapi3.SchemaRef {
+	switch {
+	case to == nil:
+		return from
+	case from == nil || from == to || to.Ref != "" && to.Ref == from.Ref:
+		return to
+	}
+
+	members := openapi3.SchemaRefs{}
+	for _, not := range []*openapi3.SchemaRef{to, from} {
+		if isAnyOfSchema(not) {
+			members = append(members, not.Value.AnyOf...)
 		} else {
-			*merged.MaxProps += *from.MaxProps
+			members = append(members, not)
 		}
-	} else {
-		merged.MaxProps = from.MaxProps
 	}
 
-	if to.AdditionalPropertiesAllowed == nil {
-		merged.AdditionalPropertiesAllowed = from.AdditionalPropertiesAllowed
-		merged.AdditionalProperties = from.AdditionalProperties
-		merged.Discriminator = from.Discriminator
+	return &openapi3.SchemaRef{Value: &openapi3.Schema{AnyOf: members}}
+}
+
+// isAnyOfSchema returns `true` when _ref_ is a inline schema with only the _anyOf_ keyword.
+func isAnyOfSchema(ref *openapi3.SchemaRef) bool {
+	if ref.Ref != "" || ref.Value == nil || len(ref.Value.AnyOf) == 0 {
+		return false
 	}
 
-	if len(from.Required) > 0 {
-		if len(merged.Required) > 0 {
-			for i := range from.Required {
-				if !ContainsString(merged.Required, from.Required[i]) {
-					merged.Required = append(merged.Required, from.Required[i])
-				}
-			}
-		} else {
-			merged.Required = from.Required
+	schema := *ref.Value
+	schema.AnyOf = nil
+
+	return schema.IsEmpty()
+}
+
+// intersectPatterns sets the _pattern_ of _merged_ to the first pattern of _to_ and _from_
+// and the rest of the patterns in the `ExtensionGoPatterns` extension.
+func intersectPatterns(merged, to, from *openapi3.Schema) {
+	patterns := []string{}
+	for _, pattern := range append(SchemaPatterns(to), SchemaPatterns(from)...) {
+		if !ContainsString(patterns, pattern) {
+			patterns = append(patterns, pattern)
 		}
 	}
 
-	if len(from.Properties) > 0 {
-		if len(to.Properties) == 0 {
-			merged.Properties = from.Properties
-		} else {
+	if len(patterns) < 2 {
+		if len(patterns) == 1 {
+			merged.Pattern = patterns[0]
+		}
 
-			for k := range from.Properties {
+		return
+	}
 
-				if _, ok := to.Properties[k]; !ok {
-					// Premiere the to property and drop from property
-					merged.Properties[k] = from.Properties[k]
-				}
-			}
+	// The extensions of _to_ are shared with _merged_
+	merged.Extensions = map[string]any{}
+	for k := range to.Extensions {
+		merged.Extensions[k] = to.Extensions[k]
+	}
+
+	merged.Pattern = patterns[0]
+	merged.Extensions[ExtensionGoPatterns] = patterns[1:]
+}
+
+// intersectTypes returns the type that satisfies both _to_ and _from_. An empty type
+// allows any type and an _integer_ is also a _number_.
+func intersectTypes(to, from string) (string, error) {
+	switch {
+	case to == "" || to == from:
+		return from, nil
+	case from == "":
+		return to, nil
+	case to == "integer" && from == "number", to == "number" && from == "integer":
+		return "integer", nil
+	}
+
+	return "", fmt.Errorf("type %s conflicts with type %s", to, from)
+}
+
+// intersectEnums returns the values that are in both _to_ and _from_. If one of them is
+// empty, i.e. any value is allowed, the other is returned.
+func intersectEnums(to, from []any) ([]any, error) {
+	if len(to) == 0 {
+		return from, nil
+	}
+
+	if len(from) == 0 {
+		return to, nil
+	}
+
+	values := []any{}
+	for i := range to {
+		if ContainsInterface(from, to[i]) {
+			values = append(values, to[i])
 		}
 	}
 
-	return &merged, nil
+	if len(values) == 0 {
+		return nil, fmt.Errorf("enums %v and %v has no value in common", to, from)
+	}
+
+	return values, nil
+}
+
+// intersectBound returns the strictest of the two bounds. When _lower_ is set it is the
+// largest minimum, otherwise the smallest maximum.
+func intersectBound(to *float64, toExclusive bool, from *float64, fromExclusive bool, lower bool) (*float64, bool) {
+	switch {
+	case to == nil:
+		return from, fromExclusive
+	case from == nil:
+		return to, toExclusive
+	case *to == *from:
+		return to, toExclusive || fromExclusive
+	case (*to > *from) == lower:
+		return to, toExclusive
+	}
+
+	return from, fromExclusive
+}
+
+// intersectCount returns the largest minimum and the smallest maximum of two counts such
+// as _minLength_ and _maxLength_.
+func intersectCount(toMin uint64, toMax *uint64, fromMin uint64, fromMax *uint64) (uint64, *uint64) {
+	min := toMin
+	if fromMin > min {
+		min = fromMin
+	}
+
+	max := toMax
+	if max == nil || (fromMax != nil && *fromMax < *max) {
+		max = fromMax
+	}
+
+	return min, max
+}
+
+// intersectMultipleOf returns the least common multiple of _to_ and _from_.
+//
+// The ratio of the two is approximated by the convergents of its continued fraction until
+// a multiple of _to_ is a multiple of _from_ within the tolerance of `types.IsMultipleOf`,
+// e.g. 0.1 and 0.3 gives 0.3 and 0.2 and 0.3 gives 0.6.
+func intersectMultipleOf(to, from *float64) *float64 {
+	switch {
+	case to == nil:
+		return from
+	case from == nil:
+		return to
+	case types.IsMultipleOf(*to, *from):
+		return to
+	case types.IsMultipleOf(*from, *to):
+		return from
+	}
+
+	// k is the denominator of the convergent
+	k, kp := 0.0, 1.0
+
+	for x, i := *to / *from, 0; i < 64; i++ {
+		a := math.Floor(x)
+		k, kp = a*k+kp, k
+
+		if lcm := *to * k; types.IsMultipleOf(lcm, *from) || x == a {
+			// Drop the rounding error of the product, e.g. 0.6000000000000001
+			lcm, _ = strconv.ParseFloat(strconv.FormatFloat(lcm, 'g', 12, 64), 64)
+			return &lcm
+		}
+
+		x = 1 / (x - a)
+	}
 
+	return to
 }
@@ -0,0 +1,26 @@
+package generator
+
+import "github.com/mariotoffia/go-openapi/generator/gentypes"
+
+// Model returns the resolved model of the specification processed by the last
+// `Generator.Generate`. It may be serialized using `gentypes.MarshalModel` for external
+// tools and plugins.
+func (ctx *GeneratorContext) Model() *gentypes.Model {
+	model := &gentypes.Model{
+		Version: gentypes.ModelVersion,
+		Specification: gentypes.OpenAPISpecificationDefinition{
+			Components: map[string]*gentypes.ComponentDefinition{},
+		},
+		Components: map[string]*gentypes.ComponentDefinition{},
+	}
+
+	for name, component := range ctx.specification.Components {
+		model.Specification.Components[name] = component
+	}
+
+	for id, component := range ctx.resolver.Components() {
+		model.Components[id] = component
+	}
+
+	return model
+}
@@ -0,0 +1,342 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// extensionModelRef is the extension of the placeholder schema that replaces a reference
+// to a model file while the specification is loaded by the openapi3.Loader.
+const extensionModelRef = "x-go-model-ref"
+
+// ModelLoader loads the schemas that are referenced from the components of a specification.
+//
+// In contrast to the openapi3.Loader, that decodes a schema in another file each time it
+// is referenced, each schema is decoded once and all references to it share the same
+// `openapi3.Schema`. Hence, references between, or within, model files may be recursive.
+type ModelLoader struct {
+	loader    *openapi3.Loader
+	documents map[string]any
+	schemas   map[string]*openapi3.Schema
+}
+
+// NewModelLoader creates a `ModelLoader` that reads the files using the `ReadFromURIFunc`
+// of _loader_.
+func NewModelLoader(loader *openapi3.Loader) *ModelLoader {
+	return &ModelLoader{
+		loader:    loader,
+		documents: map[string]any{},
+		schemas:   map[string]*openapi3.Schema{},
+	}
+}
+
+// LoadSpecification loads the specification _file_ using the openapi3.Loader, except for
+// the references to other files in _components/schemas_ that are loaded by the `ModelLoader`.
+//
+// The references keep the `$ref` as written in the file, such that the component references
+// are resolved as if loaded by the openapi3.Loader.
+//
+// NOTE: The references to other files outside of _components/schemas_, e.g. in the _paths_,
+// are still loaded by the openapi3.Loader.
+func (ml *ModelLoader) LoadSpecification(file string) (*openapi3.T, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := ml.document(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec, ok := document.(map[string]any); ok {
+		if components, ok := spec["components"].(map[string]any); ok {
+			if schemas, ok := components["schemas"].(map[string]any); ok {
+				for _, schema := range schemas {
+					detachModelRefs(schema)
+				}
+			}
+		}
+	}
+
+	// A model that references the specification shall not see the placeholders
+	delete(ml.documents, file)
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ml.loader.LoadFromDataWithPath(data, &url.URL{Path: filepath.ToSlash(file)})
+	if err != nil {
+		return nil, err
+	}
+
+	// The placeholder schema is shared with the internal references to it, e.g.
+	// #/components/schemas/<name>, hence all references to it are replaced.
+	models := map[*openapi3.Schema]*openapi3.SchemaRef{}
+	visited := map[*openapi3.Schema]bool{}
+
+	var attach func(ref *openapi3.SchemaRef) error
+	attach = func(ref *openapi3.SchemaRef) error {
+		if ref == nil || ref.Value == nil {
+			return nil
+		}
+
+		if raw, ok := ref.Value.Extensions[extensionModelRef].(json.RawMessage); ok {
+			model, ok := models[ref.Value]
+			if !ok {
+				model = &openapi3.SchemaRef{}
+				if err := json.Unmarshal(raw, &model.Ref); err != nil {
+					return err
+				}
+
+				if err := ml.Resolve(file, model); err != nil {
+					return err
+				}
+
+				models[ref.Value] = model
+			}
+
+			if ref.Ref == "" {
+				ref.Ref = model.Ref
+			}
+
+			ref.Value = model.Value
+			return nil
+		}
+
+		if visited[ref.Value] {
+			return nil
+		}
+
+		visited[ref.Value] = true
+		return visitSchemaRefs(ref.Value, attach)
+	}
+
+	for _, name := range sortedSchemaNames(doc.Components.Schemas) {
+		if err := attach(doc.Components.Schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// Resolve sets the `Value` of _ref_, that is in the _file_, to the schema it references.
+// All schemas referenced by that schema are resolved as well.
+//
+// A local reference, e.g. _#/MyType_, is in the _file_ while a relative reference, e.g.
+// _../other.yaml#/MyType_, is relative to the directory of the _file_.
+func (ml *ModelLoader) Resolve(file string, ref *openapi3.SchemaRef) error {
+	target, pointer := ref.Ref, ""
+	if idx := strings.Index(ref.Ref, "#"); idx != -1 {
+		target, pointer = ref.Ref[:idx], ref.Ref[idx+1:]
+	}
+
+	if target == "" {
+		target = file
+	} else {
+		target = filepath.Clean(filepath.Join(filepath.Dir(file), filepath.FromSlash(target)))
+	}
+
+	key := target + "#" + pointer
+	if schema, ok := ml.schemas[key]; ok {
+		ref.Value = schema
+		return nil
+	}
+
+	document, err := ml.document(target)
+	if err != nil {
+		return fmt.Errorf("reference %s in %s: %s", ref.Ref, file, err.Error())
+	}
+
+	value, err := drillPointer(document, pointer)
+	if err != nil {
+		return fmt.Errorf("reference %s in %s: %s", ref.Ref, file, err.Error())
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	schema := &openapi3.Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return fmt.Errorf("reference %s in %s: %s", ref.Ref, file, err.Error())
+	}
+
+	// Registered before its references are resolved, such that recursive references end here
+	ml.schemas[key] = schema
+	ref.Value = schema
+
+	return ml.resolveAll(target, schema)
+}
+
+// resolveAll resolves all references of the _schema_ and its inline schemas in the _file_.
+func (ml *ModelLoader) resolveAll(file string, schema *openapi3.Schema) error {
+	return visitSchemaRefs(schema, func(ref *openapi3.SchemaRef) error {
+		if ref.Ref != "" {
+			return ml.Resolve(file, ref)
+		}
+
+		if ref.Value == nil {
+			return nil
+		}
+
+		return ml.resolveAll(file, ref.Value)
+	})
+}
+
+// document returns the decoded json or yaml _file_.
+func (ml *ModelLoader) document(file string) (any, error) {
+	if document, ok := ml.documents[file]; ok {
+		return document, nil
+	}
+
+	read := ml.loader.ReadFromURIFunc
+	if read == nil {
+		read = openapi3.ReadFromFile
+	}
+
+	data, err := read(ml.loader, &url.URL{Path: filepath.ToSlash(file)})
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return nil, err
+	}
+
+	// Keep the numbers as written
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	ml.documents[file] = document
+	return document, nil
+}
+
+// visitSchemaRefs calls _visit_ for each schema reference, or inline schema, of _schema_.
+func visitSchemaRefs(schema *openapi3.Schema, visit func(ref *openapi3.SchemaRef) error) error {
+	refs := []*openapi3.SchemaRef{schema.Items, schema.AdditionalProperties, schema.Not}
+	for _, name := range sortedSchemaNames(schema.Properties) {
+		refs = append(refs, schema.Properties[name])
+	}
+
+	for _, members := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		refs = append(refs, members...)
+	}
+
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+
+		if err := visit(ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedSchemaNames returns the names of the _schemas_ in order.
+func sortedSchemaNames(schemas openapi3.Schemas) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// detachModelRefs replaces each reference to another file in the decoded _schema_, and its
+// inline schemas, with a placeholder schema, see `extensionModelRef`.
+func detachModelRefs(schema any) {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if ref, ok := object["$ref"].(string); ok {
+		if !strings.HasPrefix(ref, "#") {
+			for key := range object {
+				delete(object, key)
+			}
+
+			object[extensionModelRef] = ref
+		}
+
+		return
+	}
+
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		detachModelRefs(object[key])
+	}
+
+	if properties, ok := object["properties"].(map[string]any); ok {
+		for _, property := range properties {
+			detachModelRefs(property)
+		}
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if members, ok := object[key].([]any); ok {
+			for _, member := range members {
+				detachModelRefs(member)
+			}
+		}
+	}
+}
+
+// drillPointer returns the value at the json _pointer_ in the _document_.
+func drillPointer(document any, pointer string) (any, error) {
+	if pointer == "" {
+		return document, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("json pointer '%s' must start with '/'", pointer)
+	}
+
+	cursor := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch v := cursor.(type) {
+		case map[string]any:
+			value, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("'%s' is not found", pointer)
+			}
+
+			cursor = value
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("'%s' is not found", pointer)
+			}
+
+			cursor = v[i]
+		default:
+			return nil, fmt.Errorf("'%s' is not found", pointer)
+		}
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,280 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// HandleNot will set the `TypeDefinition.Not` when _def_ has the _not_ keyword.
+//
+// A inline _not_ schema without a type inherits the type, format, items and additional
+// properties of _def_ since it constrains the same value. Primitive _not_ schemas are
+// validated in place and are not registered as components, other inline _not_ schemas
+// are created as any other inline component.
+//
+// If the _not_ schema matches every value, the type is uninhabitable and a diagnostic is
+// added.
+func HandleNot(
+	ctx *GeneratorContext,
+	td *gentypes.TypeDefinition,
+	component *gentypes.ComponentDefinition,
+	def *openapi3.Schema) error {
+
+	if def.Not == nil {
+		return nil
+	}
+
+	not_id := component.ID.NewWithAppendTypeName("Not")
+
+	if IsReference(def.Not) {
+		ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, def.Not)
+		if err != nil {
+			return err
+		}
+
+		if err := CreateReferencedComponent(ctx, ref, def.Not); err != nil {
+			return err
+		}
+
+		td.Not = &gentypes.ComponentDefinition{ID: *not_id, Reference: ref}
+
+		if ref.Equal(&component.ID) {
+			ctx.AddDiagnostic(&component.ID, "not references the type itself, no value is valid")
+		}
+
+		return nil
+	}
+
+	schema := *def.Not.Value
+	if schema.Type == "" {
+		schema.Type = def.Type
+		schema.Format = def.Format
+	}
+
+	if schema.Type == def.Type {
+		if schema.Items == nil {
+			schema.Items = def.Items
+		}
+
+		if schema.AdditionalProperties == nil && schema.AdditionalPropertiesAllowed == nil {
+			schema.AdditionalProperties = def.AdditionalProperties
+			schema.AdditionalPropertiesAllowed = def.AdditionalPropertiesAllowed
+		}
+	}
+
+	goType, err := ResolveGoType(ctx, not_id, &schema)
+	if err != nil {
+		return err
+	}
+
+	if goType != nil && IsBuiltinGoType(goType.Name) {
+		td.Not = &gentypes.ComponentDefinition{
+			ID: *not_id,
+			Definition: &gentypes.TypeDefinition{
+				ID:          *not_id,
+				GoPackage:   td.GoPackage,
+				Schema:      &schema,
+				Composition: []gentypes.Composition{},
+				Properties:  []gentypes.Property{},
+				GoType:      goType,
+				Inline:      true,
+			},
+		}
+	} else {
+		if td.Not, err = CreateInlineComponent(ctx, not_id, &openapi3.SchemaRef{Value: &schema}); err != nil {
+			return err
+		}
+	}
+
+	if reason := uninhabitedByNot(def, &schema); reason != "" {
+		ctx.AddDiagnostic(&component.ID, "%s, no value is valid", reason)
+	}
+
+	return nil
+}
+
+// uninhabitedByNot returns the reason why the inline _not_ schema matches every value of
+// _def_, or an empty string when some values are valid.
+func uninhabitedByNot(def, not *openapi3.Schema) string {
+	if not.Type != def.Type && !(not.Type == "number" && def.Type == "integer") {
+		return ""
+	}
+
+	if len(not.Enum) > 0 {
+		if len(def.Enum) == 0 {
+			if def.Type == "boolean" && ContainsInterface(not.Enum, true) && ContainsInterface(not.Enum, false) {
+				return "not excludes both true and false"
+			}
+
+			return ""
+		}
+
+		for _, value := range def.Enum {
+			if !ContainsInterface(not.Enum, value) {
+				return ""
+			}
+		}
+
+		return "not excludes every enum value"
+	}
+
+	if IsConstrainingSchema(not) {
+		return ""
+	}
+
+	return fmt.Sprintf("not matches every %s", strings.TrimSpace(def.Type+" value"))
+}
+
+// IsConstrainingSchema returns `true` when _schema_ has any keyword, apart from type and
+// format, that limits the values it matches.
+func IsConstrainingSchema(schema *openapi3.Schema) bool {
+	return len(schema.Enum) > 0 ||
+		schema.Pattern != "" ||
+		schema.MinLength > 0 || schema.MaxLength != nil ||
+		schema.Min != nil || schema.Max != nil || schema.MultipleOf != nil ||
+		schema.MinItems > 0 || schema.MaxItems != nil || schema.UniqueItems ||
+		schema.MinProps > 0 || schema.MaxProps != nil ||
+		len(schema.Properties) > 0 || len(schema.Required) > 0 ||
+		schema.AdditionalPropertiesAllowed != nil && !*schema.AdditionalPropertiesAllowed ||
+		IsComposedSchema(schema) || schema.Not != nil
+}
+
+// NotStatements renders the statements that validates that the go expression _expr_ does
+// not match the _not_ schema of _td_. See `ValueStatements` for _path_ and _depth_.
+//
+// Primitive _not_ schemas are matched in place, where a _not_ with _anyOf_ must match neither
+// member. Other are matched by marshalling the value to json and unmarshal and validate it
+// as the _not_ type. Since a schema allows additional properties by default, unknown
+// properties do not prevent a match.
+func NotStatements(
+	ctx *GeneratorContext,
+	file *GoFile,
+	expr, path string,
+	td *gentypes.TypeDefinition,
+	depth int) ([]string, error) {
+
+	if td.Not == nil {
+		return nil, nil
+	}
+
+	not := ctx.ResolveDefinition(td.Not)
+	if not == nil {
+		return nil, fmt.Errorf("could not resolve not: %s", td.ID.String())
+	}
+
+	if td.Not.Reference != nil || ctx.resolver.ResolveComponent(&td.Not.ID) != nil {
+		notType, err := GoTypeExprOf(ctx, file, not)
+		if err != nil {
+			return nil, err
+		}
+
+		file.Import("encoding/json")
+
+		matches := []string{
+			fmt.Sprintf("data, err := json.Marshal(%s)\nif err != nil {\nreturn err\n}", expr),
+			fmt.Sprintf("var value %s\nif err := json.Unmarshal(data, &value); err != nil {\nreturn err\n}", notType),
+		}
+
+		validate, err := ValueStatements(ctx, file, "value", "", not, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{notMatch(file, path, append(matches, validate...))}, nil
+	}
+
+	// Primitive, not registered as a component
+	if td.IsPrimitive() && not.GoType.Name != td.GoType.Name &&
+		!(IsNumericGoType(not.GoType.Name) && IsNumericGoType(td.GoType.Name)) {
+		// A value of another type never matches
+		return nil, nil
+	}
+
+	statements := []string{}
+	for _, schema := range notMembers(not.Schema) {
+		member := *not
+		member.Schema = schema
+
+		matches, err := primitiveMatches(ctx, file, expr, &member, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, notMatch(file, path, matches))
+	}
+
+	return statements, nil
+}
+
+// notMembers returns the schemas that a value must match neither of. It is the _not_ schema
+// itself or, when it has _anyOf_, each member intersected with the _not_ schema. A member of
+// another type or that can not be intersected never matches and is left out.
+func notMembers(not *openapi3.Schema) []*openapi3.Schema {
+	if len(not.AnyOf) == 0 {
+		return []*openapi3.Schema{not}
+	}
+
+	outer := *not
+	outer.AnyOf = nil
+
+	members := []*openapi3.Schema{}
+	for _, ref := range not.AnyOf {
+		if ref.Value == nil {
+			continue
+		}
+
+		if member, err := IntersectSchemas(nil, &outer, ref.Value, ""); err == nil {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// primitiveMatches renders the statements, within a function that returns an error, that
+// returns an error when _expr_ does not match the primitive _not_ type.
+func primitiveMatches(
+	ctx *GeneratorContext,
+	file *GoFile,
+	expr string,
+	not *gentypes.TypeDefinition,
+	depth int) ([]string, error) {
+
+	matches := []string{}
+
+	if len(not.Schema.Enum) > 0 {
+		literals := []string{}
+		for _, value := range not.Schema.Enum {
+			literal, err := GoLiteral(not.GoType.Name, value)
+			if err != nil {
+				return nil, fmt.Errorf("not %s: %s", not.ID.String(), err.Error())
+			}
+
+			literals = append(literals, literal)
+		}
+
+		matches = append(matches, fmt.Sprintf(
+			"switch %s {\ncase %s:\ndefault:\nreturn %s\n}",
+			expr, strings.Join(literals, ", "), errorf(file, "", "not in enum"),
+		))
+	}
+
+	constraints, err := ConstraintStatements(ctx, file, expr, "", not, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(matches, constraints...), nil
+}
+
+// notMatch renders the statement that returns an error when the _matches_ statements, see
+// `primitiveMatches`, do not return an error.
+func notMatch(file *GoFile, path string, matches []string) string {
+	return fmt.Sprintf(
+		"if err := func() error {\n%s\nreturn nil\n}(); err == nil {\nreturn %s\n}",
+		joinStatements(matches), errorf(file, path, "must not match the not schema"),
+	)
+}
@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// ExtensionGoOptional overrides the `OptionalPolicy` of a single property, e.g.
+// `x-go-optional: generic`. It is set on the inline property schema.
+const ExtensionGoOptional = "x-go-optional"
+
+// OptionalPolicy is how optional and nullable properties are represented in go.
+type OptionalPolicy string
+
+const (
+	// OptionalPointer renders optional and nullable properties as pointers where `nil` is
+	// absent or `null`. This is the default.
+	OptionalPointer OptionalPolicy = "pointer"
+	// OptionalValue renders optional properties of builtin go types as values with
+	// `omitempty`, i.e. the zero value is absent. Nullable properties and other types are
+	// rendered as pointers.
+	OptionalValue OptionalPolicy = "value"
+	// OptionalGeneric renders optional properties as `types.Optional[T]` and nullable
+	// properties as `types.Nullable[T]` that distinguishes absent, `null` and a value.
+	OptionalGeneric OptionalPolicy = "generic"
+)
+
+// Presence is how the go field of a property represents an absent or `null` value.
+type Presence int
+
+const (
+	// PresenceValue is a plain value. It is absent when the zero value, or `nil` for
+	// slices, maps and interfaces.
+	PresenceValue Presence = iota
+	// PresencePointer is a pointer to the value.
+	PresencePointer
+	// PresenceOptional is a `types.Optional[T]`.
+	PresenceOptional
+	// PresenceNullable is a `types.Nullable[T]`.
+	PresenceNullable
+)
+
+// IsGeneric returns `true` when the presence is a `types.Optional` or `types.Nullable`.
+func (p Presence) IsGeneric() bool {
+	return p == PresenceOptional || p == PresenceNullable
+}
+
+// Wrap renders the field type of the go type expression _goType_.
+func (p Presence) Wrap(file *GoFile, goType string) string {
+	switch p {
+	case PresencePointer:
+		return "*" + goType
+	case PresenceOptional:
+		file.Import(RuntimePackage)
+		return "types.Optional[" + goType + "]"
+	case PresenceNullable:
+		file.Import(RuntimePackage)
+		return "types.Nullable[" + goType + "]"
+	}
+
+	return goType
+}
+
+// PropertyPresence returns how the _property_ of type _td_ is represented in go.
+//
+// The `OptionalPolicy` is set by `Settings.UseOptionalPolicy` and may be overridden per
+// property using the `x-go-optional` extension. A property that breaks a reference cycle,
+// see `Property.Pointer`, is always a pointer.
+//
+// Slices, maps and interfaces already represents absence by `nil` and are only wrapped
+// when the policy is `OptionalGeneric` and the property is nullable.
+func PropertyPresence(
+	ctx *GeneratorContext,
+	property *gentypes.Property,
+	td *gentypes.TypeDefinition) (Presence, error) {
+
+	policy := ctx.settings.optional_policy
+	if policy == "" {
+		policy = OptionalPointer
+	}
+
+	if property.Definition != nil && property.Reference == nil {
+		if value, ok := property.Definition.Schema.Extensions[ExtensionGoOptional]; ok {
+			name, ok := GetExtensionString(property.Definition.Schema, ExtensionGoOptional)
+			if !ok {
+				return PresenceValue, fmt.Errorf(
+					"%s must be a string, got: %v (%s)", ExtensionGoOptional, value, property.ID.String(),
+				)
+			}
+
+			policy = OptionalPolicy(name)
+		}
+	}
+
+	nullable := td.Schema != nil && td.Schema.Nullable
+
+	switch policy {
+	case OptionalPointer, OptionalValue, OptionalGeneric:
+	default:
+		return PresenceValue, fmt.Errorf("unknown optional policy: %s (%s)", policy, property.ID.String())
+	}
+
+	switch {
+	case property.Pointer && !IsNillable(td):
+		return PresencePointer, nil
+	case property.Required && !nullable:
+		return PresenceValue, nil
+	case policy == OptionalGeneric && nullable:
+		return PresenceNullable, nil
+	case IsNillable(td):
+		return PresenceValue, nil
+	case policy == OptionalGeneric:
+		return PresenceOptional, nil
+	case policy == OptionalValue && !nullable && td.IsPrimitive() && IsBuiltinGoType(td.GoType.Name):
+		return PresenceValue, nil
+	}
+
+	return PresencePointer, nil
+}
+
+// zeroLiteral returns the go literal of the zero value of the builtin go type _goType_.
+func zeroLiteral(goType string) string {
+	switch {
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case IsNumericGoType(goType):
+		return "0"
+	}
+
+	return "nil"
+}
+
+// PresenceCondition renders the condition that is `true` when the _field_ of type _td_, in
+// the go expression _expr_, is present or absent when _present_ is `false`. An empty string
+// is returned when it can not be decided.
+//
+// A `types.Nullable` that is `null` is considered present.
+func PresenceCondition(field *GoField, td *gentypes.TypeDefinition, expr string, present bool) string {
+	op, not := "!=", ""
+	if !present {
+		op, not = "==", "!"
+	}
+
+	switch {
+	case field.Presence.IsGeneric():
+		return not + expr + ".Present"
+	case field.Presence == PresencePointer, IsNillable(td):
+		return expr + " " + op + " nil"
+	case td.IsPrimitive() && IsBuiltinGoType(td.GoType.Name):
+		return expr + " " + op + " " + zeroLiteral(td.GoType.Name)
+	}
+
+	return ""
+}
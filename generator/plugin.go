@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// PluginProtocolVersion is the version of the `PluginRequest` and `PluginResponse` exchanged
+// with plugins.
+const PluginProtocolVersion = "1"
+
+// Plugin is an external executable that generates files from the resolved model, similar to
+// a _protoc_ plugin. The plugin reads a `PluginRequest` as json from _stdin_ and writes a
+// `PluginResponse` as json to _stdout_, see `ServePlugin`.
+type Plugin struct {
+	// Name is the name of the plugin used in errors. The command is used when empty.
+	Name string
+	// Command is the executable, either a path or a name that is looked up in _PATH_.
+	Command string
+	// Args are the command line arguments of the executable.
+	Args []string
+	// Env are additional environment variables, as _key=value_, of the executable.
+	Env []string
+	// Parameters are passed to the plugin in `PluginRequest.Parameters`.
+	Parameters map[string]string
+}
+
+// PluginRequest is written as json to the _stdin_ of a plugin.
+type PluginRequest struct {
+	// Version is the `PluginProtocolVersion`.
+	Version string `json:"version"`
+	// Parameters are the `Plugin.Parameters`.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// Model is the resolved model serialized as json, see `gentypes.MarshalModel`.
+	Model json.RawMessage `json:"model"`
+}
+
+// DecodeModel reads the `Model` of the request, see `gentypes.UnmarshalModel`.
+func (req *PluginRequest) DecodeModel() (*gentypes.Model, error) {
+	return gentypes.UnmarshalModel(req.Model, gentypes.ModelFormatJSON)
+}
+
+// PluginResponse is written as json by a plugin to its _stdout_.
+type PluginResponse struct {
+	// Files are the files to write, relative to the output path.
+	Files []PluginFile `json:"files,omitempty"`
+	// Error is set when the plugin failed, the files are then ignored.
+	Error string `json:"error,omitempty"`
+}
+
+// PluginFile is a file generated by a plugin.
+type PluginFile struct {
+	// Path is the path of the file relative to the output path. It must not be outside of
+	// the output path.
+	Path string `json:"path"`
+	// Content is the content of the file.
+	Content string `json:"content"`
+}
+
+// RunPlugins runs the plugins in the settings and returns the files that they generated. The
+// plugins are run in the order they were added.
+//
+// CAUTION: A file of a plugin may not have the same path as a file in _generated_ or a file
+// of another plugin.
+func RunPlugins(ctx *GeneratorContext, generated []GeneratedFile) ([]GeneratedFile, error) {
+	if len(ctx.settings.plugins) == 0 {
+		return nil, nil
+	}
+
+	model, err := gentypes.MarshalModel(ctx.Model(), gentypes.ModelFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	for _, file := range generated {
+		paths[filepath.Clean(file.Path)] = "the generator"
+	}
+
+	files := []GeneratedFile{}
+
+	for _, plugin := range ctx.settings.plugins {
+		name := plugin.Name
+		if name == "" {
+			name = plugin.Command
+		}
+
+		response, err := RunPlugin(plugin, &PluginRequest{
+			Version:    PluginProtocolVersion,
+			Parameters: plugin.Parameters,
+			Model:      model,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("plugin: %s failed: %s", name, err.Error())
+		}
+
+		for _, file := range response.Files {
+			path := filepath.Clean(file.Path)
+
+			if filepath.IsAbs(path) || path == "." || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+				return nil, fmt.Errorf("plugin: %s file: %s is not within the output path", name, file.Path)
+			}
+
+			if other, ok := paths[path]; ok {
+				return nil, fmt.Errorf("plugin: %s file: %s is already generated by %s", name, file.Path, other)
+			}
+
+			paths[path] = "plugin: " + name
+			files = append(files, GeneratedFile{Path: path, Content: []byte(file.Content)})
+		}
+	}
+
+	return files, nil
+}
+
+// RunPlugin executes the _plugin_ with the _request_ and returns its response. An error is
+// returned when the plugin exits with an error or responds with `PluginResponse.Error`.
+func RunPlugin(plugin Plugin, request *PluginRequest) (*PluginResponse, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(plugin.Command, plugin.Args...)
+	cmd.Env = append(os.Environ(), plugin.Env...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s: %s", err.Error(), message)
+		}
+
+		return nil, err
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("invalid response: %s", err.Error())
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+
+	return &response, nil
+}
+
+// ServePlugin implements a plugin in go. It reads the `PluginRequest` from _in_, invokes
+// _generate_ with the request and its decoded model and writes the `PluginResponse` to _out_.
+// An error from _generate_ is written as `PluginResponse.Error`.
+//
+// A plugin is usually a main package that invokes:
+//
+//	generator.ServePlugin(os.Stdin, os.Stdout, generate)
+func ServePlugin(
+	in io.Reader,
+	out io.Writer,
+	generate func(request *PluginRequest, model *gentypes.Model) ([]PluginFile, error)) error {
+
+	respond := func(response PluginResponse) error {
+		return json.NewEncoder(out).Encode(response)
+	}
+
+	var request PluginRequest
+	if err := json.NewDecoder(in).Decode(&request); err != nil {
+		return respond(PluginResponse{Error: "invalid request: " + err.Error()})
+	}
+
+	if request.Version != PluginProtocolVersion {
+		return respond(PluginResponse{Error: fmt.Sprintf(
+			"unsupported plugin protocol version: %s, expected: %s", request.Version, PluginProtocolVersion,
+		)})
+	}
+
+	model, err := request.DecodeModel()
+	if err != nil {
+		return respond(PluginResponse{Error: err.Error()})
+	}
+
+	files, err := generate(&request, model)
+	if err != nil {
+		return respond(PluginResponse{Error: err.Error()})
+	}
+
+	return respond(PluginResponse{Files: files})
+}
+
+// sortFiles sorts the _files_ on path.
+func sortFiles(files []GeneratedFile) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
@@ -47,7 +47,10 @@ func HandleDiscriminatorBasedPolymorphism(
 		}
 	}
 
-	mapping_table := CreateMappingTable(ctx, componentId, def)
+	mapping_table, err := CreateMappingTable(ctx, componentId, def)
+	if err != nil {
+		return err
+	}
 
 	get_map_from := func(ref *gentypes.ComponentReference) string {
 		for from, map_ref := range mapping_table {
@@ -60,12 +63,14 @@ func HandleDiscriminatorBasedPolymorphism(
 	}
 
 	for i := range def.OneOf {
-		ref := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, def.OneOf[i])
+		ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, def.OneOf[i])
+		if err != nil {
+			return err
+		}
+
 		// Make sure the the _ref_ is created
-		if ctx.resolver.ResolveComponent(ref) == nil {
-			if _, err := CreateComponentFromReference(ctx, ref, def.OneOf[i]); err != nil {
-				return err
-			}
+		if err := CreateReferencedComponent(ctx, ref, def.OneOf[i]); err != nil {
+			return err
 		}
 
 		td.DiscriminatorComponents = append(td.DiscriminatorComponents, gentypes.DiscriminatorComponent{
@@ -90,24 +95,26 @@ func HandleDiscriminatorBasedPolymorphism(
 // NOTE: Some entries may have a namespace since referenced in same module (file).
 //
 // Entries that have been added will be resolved so they too have a `ComponentReference` and not just
-// a `SchemaRef`.
+// a `SchemaRef`. An error is returned when a reference can not be resolved.
 func CreateMappingTable(
 	ctx *GeneratorContext,
 	componentId *gentypes.ComponentReference,
-	schema *openapi3.Schema) map[string]gentypes.ComponentReference {
+	schema *openapi3.Schema) (map[string]gentypes.ComponentReference, error) {
 
 	mapping := map[string]gentypes.ComponentReference{}
 
 	if schema.Discriminator == nil || schema.Discriminator.PropertyName == "" {
-		return mapping
+		return mapping, nil
 	}
 
 	for name, reference := range schema.Discriminator.Mapping {
 
-		mapping[name] = *ResolveReferenceAndSwitchIfNeeded(
-			ctx, componentId, &openapi3.SchemaRef{Ref: reference},
-		)
+		ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, &openapi3.SchemaRef{Ref: reference})
+		if err != nil {
+			return nil, err
+		}
 
+		mapping[name] = *ref
 	}
 
 	finder := func(ref *gentypes.ComponentReference) bool {
@@ -124,7 +131,11 @@ func CreateMappingTable(
 	if len(mapping) < len(schema.OneOf) {
 		for i := range schema.OneOf {
 
-			ref := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, schema.OneOf[i])
+			ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, componentId, schema.OneOf[i])
+			if err != nil {
+				return nil, err
+			}
+
 			if _, ok := mapping[ref.TypeName]; ok {
 				continue
 			}
@@ -135,5 +146,5 @@ func CreateMappingTable(
 		}
 	}
 
-	return mapping
+	return mapping, nil
 }
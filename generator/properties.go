@@ -1,7 +1,7 @@
 package generator
 
 import (
-	"fmt"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mariotoffia/go-openapi/generator/gentypes"
@@ -14,7 +14,6 @@ func HandleProperties(
 	def *openapi3.Schema) error {
 
 	property_objects := openapi3.Schemas{}
-	var err error
 
 	for propertyName := range def.Properties {
 
@@ -28,12 +27,13 @@ func HandleProperties(
 		// Reference to other type.
 		property_id := component.ID.NewWithAppendTypeName(propertyName)
 
-		ref := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, property)
+		ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, property)
+		if err != nil {
+			return err
+		}
 
-		if ctx.resolver.ResolveComponent(&component.ID) != nil {
-			if _, err = CreateComponentFromReference(ctx, ref, property); err != nil {
-				return err
-			}
+		if err = CreateReferencedComponent(ctx, ref, property); err != nil {
+			return err
 		}
 
 		td.Properties = append(td.Properties, gentypes.Property{
@@ -52,9 +52,10 @@ func HandleProperties(
 
 		property_id := component.ID.NewWithAppendTypeName(propertyName)
 
-		if property.Value.Type == "object" {
-			// Create a new component for the property
-			ref, err := CreateComponentFromReference(ctx, property_id, property)
+		if property.Value.Type == "object" || IsComposedSchema(property.Value) {
+			// Create a new component for the property, composed schemas get the same
+			// composition and polymorphism handling as top-level components.
+			ref, err := CreateInlineComponent(ctx, property_id, property)
 			if err != nil {
 				return err
 			}
@@ -68,46 +69,47 @@ func HandleProperties(
 			continue
 		}
 
-		// Handle all other types except arrays
-		if property.Value.Type != "array" {
-			td.Properties = append(td.Properties, gentypes.Property{
-				ComponentDefinition: gentypes.ComponentDefinition{
-					ID: *property_id,
-					Definition: &gentypes.TypeDefinition{
-						ID:          *property_id,
-						GoPackage:   td.GoPackage,
-						Schema:      property.Value,
-						Composition: []gentypes.Composition{},
-						Properties:  []gentypes.Property{},
-					},
-				},
-				Required:     ContainsString(def.Required, propertyName),
-				PropertyName: propertyName,
-			})
-
+		goType, err := ResolveGoType(ctx, property_id, property.Value)
+		if err != nil {
+			return err
 		}
 
-		if property.Value.Items == nil {
-			continue
+		property_component := gentypes.ComponentDefinition{
+			ID: *property_id,
+			Definition: &gentypes.TypeDefinition{
+				ID:          *property_id,
+				GoPackage:   td.GoPackage,
+				Schema:      property.Value,
+				Composition: []gentypes.Composition{},
+				Properties:  []gentypes.Property{},
+				GoType:      goType,
+				Inline:      true,
+			},
 		}
 
-		// Array
-		property_array_id := property_id.NewWithAppendTypeName("Array")
-		if ctx.resolver.ResolveComponent(property_array_id) != nil {
-			return fmt.Errorf("array already defined: %s", property_array_id)
+		if property.Value.Type == "array" && property.Value.Items != nil {
+			err := HandleArrayDefinition(ctx, property_component.Definition, &property_component, property.Value)
+			if err != nil {
+				return err
+			}
 		}
 
-		ref, err := HandleArray(ctx, property_array_id, property.Value.Items)
+		err = HandleNot(ctx, property_component.Definition, &property_component, property.Value)
 		if err != nil {
 			return err
 		}
 
 		td.Properties = append(td.Properties, gentypes.Property{
-			ComponentDefinition: *ref,
+			ComponentDefinition: property_component,
 			Required:            ContainsString(def.Required, propertyName),
 			PropertyName:        propertyName,
 		})
 	}
 
+	// Keep the properties in a stable order
+	sort.SliceStable(td.Properties, func(i, j int) bool {
+		return td.Properties[i].PropertyName < td.Properties[j].PropertyName
+	})
+
 	return nil
 }
@@ -0,0 +1,435 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// GeneratedFile is a file produced when rendering the specification.
+type GeneratedFile struct {
+	// Path is the path of the file relative to the output path.
+	Path string
+	// Content is the content of the file.
+	Content []byte
+}
+
+// GoImport is a import in a `GoFile`.
+type GoImport struct {
+	// Alias is set when the package name is not the same as the last
+	// element of the _Path_.
+	Alias string
+	// Path is the fully qualified go package.
+	Path string
+}
+
+// GoFile is the model of a go source file when rendered. Each _OpenAPI_
+// module (file) is rendered into one go file.
+type GoFile struct {
+	// Path is the path of the file relative to the output path.
+	Path string
+	// Package is the go package name.
+	Package string
+	// GoPackage is the fully qualified go package.
+	GoPackage string
+	// Imports are the imports, sorted on path, used by the `Types`.
+	Imports []GoImport
+	// Vars are package level variable declarations.
+	Vars []string
+	// Types are the types to render.
+	Types []*GoTypeDecl
+	// Funcs are package level functions that are rendered after the types.
+	Funcs []string
+
+	imports map[string]string
+}
+
+// NewGoFile creates a new go file for the module that _td_ is declared in.
+func NewGoFile(td *gentypes.TypeDefinition) *GoFile {
+	return &GoFile{
+		Path:      filepath.Join(td.ID.Path, strcase.ToSnake(td.ID.Module)+".go"),
+		Package:   GoPackageName(td.GoPackage),
+		GoPackage: td.GoPackage,
+		imports:   map[string]string{},
+	}
+}
+
+// Import adds the _goPackage_ to the imports and returns the name that
+// qualifies types in that package. If it is the package of the file itself
+// an empty string is returned.
+func (f *GoFile) Import(goPackage string) string {
+	if goPackage == "" || goPackage == f.GoPackage {
+		return ""
+	}
+
+	if name, ok := f.imports[goPackage]; ok {
+		return name
+	}
+
+	name := GoPackageName(goPackage)
+	alias := name
+
+	for i := 2; f.isImportNameTaken(alias); i++ {
+		alias = fmt.Sprintf("%s%d", name, i)
+	}
+
+	f.imports[goPackage] = alias
+	return alias
+}
+
+// Qualify returns the _typeName_ qualified with the package name of _goPackage_
+// and makes sure that it is imported.
+func (f *GoFile) Qualify(goPackage, typeName string) string {
+	if name := f.Import(goPackage); name != "" {
+		return name + "." + typeName
+	}
+
+	return typeName
+}
+
+// AddVar adds a package level variable declaration unless already added.
+func (f *GoFile) AddVar(declaration string) {
+	if !ContainsString(f.Vars, declaration) {
+		f.Vars = append(f.Vars, declaration)
+	}
+}
+
+func (f *GoFile) isImportNameTaken(name string) bool {
+	if name == f.Package {
+		return true
+	}
+
+	for _, alias := range f.imports {
+		if alias == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveImports will populate the `Imports` from the imported packages.
+func (f *GoFile) resolveImports() {
+	f.Imports = []GoImport{}
+
+	for path, name := range f.imports {
+		imp := GoImport{Path: path}
+		if name != filepath.Base(path) {
+			imp.Alias = name
+		}
+
+		f.Imports = append(f.Imports, imp)
+	}
+
+	sort.Slice(f.Imports, func(i, j int) bool {
+		return f.Imports[i].Path < f.Imports[j].Path
+	})
+}
+
+var (
+	majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+	gopkgVersion = regexp.MustCompile(`\.v[0-9]+$`)
+)
+
+// GoPackageName returns the go package name of the fully qualified _goPackage_.
+//
+// NOTE: Major version suffixes such as _/v2_ and _.v3_ are not part of the name.
+func GoPackageName(goPackage string) string {
+	name := filepath.Base(goPackage)
+	if majorVersion.MatchString(name) && filepath.Dir(goPackage) != "." {
+		name = filepath.Base(filepath.Dir(goPackage))
+	}
+
+	name = strings.ToLower(strings.TrimPrefix(gopkgVersion.ReplaceAllString(name, ""), "go-"))
+
+	var sb strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+
+	name = sb.String()
+	if name == "" {
+		return "models"
+	}
+
+	if name[0] >= '0' && name[0] <= '9' {
+		return "p" + name
+	}
+
+	return name
+}
+
+// Render will render all types in the specification into go source files. There
+// is one go file per module (file) and one go package per module directory.
+//
+// The files are sorted on path and all go files are formatted. The declarations and the
+// rendered files are passed to the visitors, see `Visitor`.
+func Render(ctx *GeneratorContext) ([]GeneratedFile, error) {
+	tpl, err := ctx.settings.templates.GetGoTemplates(template.FuncMap{})
+	if err != nil {
+		return nil, err
+	}
+
+	var faker ValueFaker
+	if ctx.settings.round_trips && ctx.settings.faker != nil {
+		faker = ctx.settings.faker(ctx)
+	}
+
+	files := map[string]*GoFile{}
+
+	get := func(file *GoFile) *GoFile {
+		if existing, ok := files[file.Path]; ok {
+			return existing
+		}
+
+		files[file.Path] = file
+		return file
+	}
+
+	for _, td := range NamedTypeDefinitions(ctx) {
+		file := get(NewGoFile(td))
+
+		decl, err := DeclareGoType(ctx, file, td)
+		if err != nil {
+			return nil, err
+		}
+
+		file.Types = append(file.Types, decl)
+
+		variants, err := DeclareVariants(ctx, file, td, decl)
+		if err != nil {
+			return nil, err
+		}
+
+		file.Types = append(file.Types, variants...)
+
+		patch, err := DeclarePatch(ctx, file, td, decl)
+		if err != nil {
+			return nil, err
+		}
+
+		if patch != nil {
+			file.Types = append(file.Types, patch)
+		}
+
+		if ctx.settings.examples {
+			if err := DeclareExamples(ctx, get(NewExampleFile(td)), td, decl); err != nil {
+				return nil, err
+			}
+		}
+
+		if ctx.settings.round_trips {
+			if err := DeclareRoundTrips(ctx, faker, get(NewRoundTripFile(td)), td, decl); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path, file := range files {
+		if len(file.Types) > 0 || len(file.Funcs) > 0 {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	for _, visitor := range ctx.settings.visitors {
+		for _, path := range paths {
+			for _, decl := range files[path].Types {
+				if err := visitor.BeforeRender(ctx, files[path], decl); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	generated := make([]GeneratedFile, 0, len(paths))
+
+	for _, path := range paths {
+		source, err := renderGoFile(tpl, files[path])
+		if err != nil {
+			return nil, err
+		}
+
+		generated = append(generated, GeneratedFile{Path: path, Content: source})
+	}
+
+	for _, visitor := range ctx.settings.visitors {
+		if generated, err = visitor.AfterRender(ctx, generated); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ctx.settings.visitors) > 0 {
+		sortFiles(generated)
+	}
+
+	return generated, nil
+}
+
+// renderGoFile renders and formats the _file_. Since imports are added while declaring
+// the types, some may not be used. Hence, the file is rendered a second time without the
+// unused imports.
+func renderGoFile(tpl *template.Template, file *GoFile) ([]byte, error) {
+	execute := func() ([]byte, error) {
+		file.resolveImports()
+
+		var buf bytes.Buffer
+		if err := tpl.ExecuteTemplate(&buf, string(TemplateGoFile), file); err != nil {
+			return nil, fmt.Errorf("failed to render: %s error: %s", file.Path, err.Error())
+		}
+
+		source, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to format: %s error: %s\n%s", file.Path, err.Error(), buf.String(),
+			)
+		}
+
+		return source, nil
+	}
+
+	source, err := execute()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parser.ParseFile(token.NewFileSet(), file.Path, source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(parsed, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	pruned := false
+	for path, name := range file.imports {
+		if !used[name] {
+			delete(file.imports, path)
+			pruned = true
+		}
+	}
+
+	if !pruned {
+		return source, nil
+	}
+
+	return execute()
+}
+
+// WriteFiles writes the _files_ relative to the _output_ path.
+func WriteFiles(output string, files []GeneratedFile) error {
+	for _, file := range files {
+		path := filepath.Join(output, file.Path)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, file.Content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NamedTypeDefinitions returns all type definitions that are rendered as named go
+// types, sorted on their id. This includes inline types that needs a name such as
+// inline objects and enums.
+//
+// NOTE: Types in another go package than the packages in the settings are not included, i.e.
+// types registered by another generation that shares the resolver, see
+// `Settings.UseReferenceResolver`.
+func NamedTypeDefinitions(ctx *GeneratorContext) []*gentypes.TypeDefinition {
+	seen := map[string]bool{}
+	named := []*gentypes.TypeDefinition{}
+
+	var visit func(td *gentypes.TypeDefinition)
+	visit = func(td *gentypes.TypeDefinition) {
+		if td == nil || seen[td.ID.String()] {
+			return
+		}
+
+		seen[td.ID.String()] = true
+
+		if IsNamedType(td) && IsOwnPackage(ctx, td.GoPackage) {
+			named = append(named, td)
+		}
+
+		for i := range td.Properties {
+			visit(td.Properties[i].Definition)
+		}
+
+		if td.Items != nil {
+			visit(td.Items.Definition)
+		}
+
+		if td.AdditionalProperties != nil {
+			visit(td.AdditionalProperties.Definition)
+		}
+	}
+
+	for _, component := range ctx.resolver.Components() {
+		visit(component.Definition)
+	}
+
+	sort.Slice(named, func(i, j int) bool {
+		return named[i].ID.String() < named[j].ID.String()
+	})
+
+	return named
+}
+
+// IsOwnPackage returns `true` if the _goPackage_ is, or is within, the model or specification
+// package in the settings. It is always `true` when no package is set.
+func IsOwnPackage(ctx *GeneratorContext, goPackage string) bool {
+	packages := []string{}
+	for _, pkg := range []string{ctx.settings.model_package, ctx.settings.spec_package} {
+		if pkg != "" {
+			packages = append(packages, pkg)
+		}
+	}
+
+	if len(packages) == 0 {
+		return true
+	}
+
+	for _, pkg := range packages {
+		if goPackage == pkg || strings.HasPrefix(goPackage, pkg+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNamedType returns `true` if the _td_ is rendered as a named go type. All types that
+// has a name in the specification are named, inline types are named when they are
+// objects, enums or unions. Other inline types such as arrays are rendered in place.
+func IsNamedType(td *gentypes.TypeDefinition) bool {
+	return !td.Inline || td.IsObject() || td.IsEnum() || td.IsUnion()
+}
@@ -0,0 +1,238 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+	"github.com/mariotoffia/go-openapi/types"
+)
+
+// declareDefaults adds the `New<Type>` constructor and the `ApplyDefaults` method to the
+// struct _decl_.
+//
+// The constructor takes the required properties, in declaration order, as parameters and
+// applies the defaults. `ApplyDefaults` sets each optional property that is not set to the
+// _default_ in the schema and applies the defaults of all nested structs.
+func declareDefaults(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	scratch := &GoTypeDecl{}
+	params := []string{}
+	assign := []string{}
+
+	for _, property := range AllProperties(ctx, td) {
+		if !property.Required {
+			continue
+		}
+
+		if err := declareField(ctx, file, property, scratch); err != nil {
+			return err
+		}
+
+		field := scratch.Fields[len(scratch.Fields)-1]
+
+		param := strcase.ToLowerCamel(field.Name)
+		if token.IsKeyword(param) || param == "result" {
+			param += "Value"
+		}
+
+		params = append(params, param+" "+field.Type)
+		assign = append(assign, fmt.Sprintf("result.%s = %s", field.Name, param))
+	}
+
+	decl.Methods = append(decl.Methods, fmt.Sprintf(
+		"// New%s creates a %s with the required properties set and the defaults applied.\n"+
+			"func New%s(%s) %s {\nvar result %s\n%s\nresult.ApplyDefaults()\nreturn result\n}",
+		decl.Name, decl.Name,
+		decl.Name, strings.Join(params, ", "), decl.Name, decl.Name, joinStatements(assign),
+	))
+
+	statements := []string{}
+
+	for _, field := range decl.Fields {
+		if field.Embedded {
+			statements = append(statements, fmt.Sprintf("v.%s.ApplyDefaults()", field.Name))
+			continue
+		}
+
+		if field.Property == nil {
+			continue
+		}
+
+		property_td := ctx.ResolveDefinition(&field.Property.ComponentDefinition)
+		if property_td == nil {
+			return fmt.Errorf("could not resolve property: %s", field.Property.ID.String())
+		}
+
+		if !field.Property.Required && property_td.Schema.Default != nil {
+			apply, err := defaultStatement(ctx, file, td, field, property_td)
+			if err != nil {
+				return err
+			}
+
+			if apply != "" {
+				statements = append(statements, apply)
+			}
+		}
+
+		statements = append(statements, nestedDefaultStatements(ctx, field, property_td)...)
+	}
+
+	decl.Methods = append(decl.Methods, fmt.Sprintf(
+		"// ApplyDefaults sets all optional properties that are not set to their default value.\n"+
+			"func (v *%s) ApplyDefaults() {%s}",
+		decl.Name, blockStatements(statements),
+	))
+
+	decl.DefaultsOnUnmarshal = ctx.settings.defaults_on_unmarshal
+	if decl.DefaultsOnUnmarshal {
+		file.Import("encoding/json")
+	}
+
+	return nil
+}
+
+// defaultDecoders creates the values, keyed on the go types of the default type mapping
+// that are not generated, that a default is unmarshalled into when generating. This verifies
+// that the default, which passes the schema validation, also is accepted by the go type.
+var defaultDecoders = map[gentypes.GoType]func() any{
+	{Name: "time.Time", Import: "time"}:          func() any { return &time.Time{} },
+	{Name: "types.Date", Import: RuntimePackage}: func() any { return &types.Date{} },
+	{Name: "types.UUID", Import: RuntimePackage}: func() any { return &types.UUID{} },
+}
+
+// defaultStatement renders the statement that sets the _field_, of the struct _parent_, to
+// the default value of the schema when it is absent, see `PresenceCondition`. Defaults of
+// builtin go types are rendered as literals, other are unmarshalled from the json of the
+// default.
+//
+// A default that does not match its schema is not applied, see `CheckExamples`. A default
+// that can not be unmarshalled into its go type, see `defaultDecoders`, is added as a
+// diagnostic at the default and is not applied.
+func defaultStatement(
+	ctx *GeneratorContext,
+	file *GoFile,
+	parent *gentypes.TypeDefinition,
+	field *GoField,
+	td *gentypes.TypeDefinition) (string, error) {
+
+	unset := PresenceCondition(field, td, "v."+field.Name, false)
+	if unset == "" {
+		// The zero value is a valid value
+		return "", nil
+	}
+
+	if err := td.Schema.VisitJSON(td.Schema.Default); err != nil {
+		// Not applied, reported by `CheckExamples`
+		return "", nil
+	}
+
+	goType, err := GoTypeExprOf(ctx, file, td)
+	if err != nil {
+		return "", err
+	}
+
+	assign := fmt.Sprintf("v.%s = value", field.Name)
+	switch {
+	case field.Presence == PresencePointer:
+		assign = fmt.Sprintf("v.%s = &value", field.Name)
+	case field.Presence.IsGeneric():
+		assign = fmt.Sprintf("v.%s.Set(value)", field.Name)
+	}
+
+	if td.IsPrimitive() && IsBuiltinGoType(td.GoType.Name) && td.GoType.Name != "[]byte" {
+		literal, err := GoLiteral(td.GoType.Name, td.Schema.Default)
+		if err != nil {
+			return "", fmt.Errorf("default %s: %s", td.ID.String(), err.Error())
+		}
+
+		return fmt.Sprintf(
+			"if %s {\nvar value %s = %s\n%s\n}", unset, goType, literal, assign,
+		), nil
+	}
+
+	data, err := json.Marshal(td.Schema.Default)
+	if err != nil {
+		return "", fmt.Errorf("default %s: %s", td.ID.String(), err.Error())
+	}
+
+	if err := decodeDefault(td, data); err != nil {
+		root, pointer := &parent.ID, "/properties/"+EscapePointerToken(field.Property.PropertyName)+"/default"
+		if field.Property.Reference != nil {
+			root, pointer = field.Property.Reference, "/default"
+		}
+
+		ctx.AddDiagnosticAt(
+			root, ctx.Locate(root, pointer),
+			"default is not a valid %s, it is not applied: %s", td.GoType.Name, err.Error(),
+		)
+
+		return "", nil
+	}
+
+	file.Import("encoding/json")
+
+	return fmt.Sprintf(
+		"if %s {\nvar value %s\nif err := json.Unmarshal([]byte(%s), &value); err == nil {\n%s\n}\n}",
+		unset, goType, strconv.Quote(string(data)), assign,
+	), nil
+}
+
+// decodeDefault unmarshals the json _data_ of the default of _td_ when its go type is one of
+// the `defaultDecoders`. Other defaults are only validated against the schema, see
+// `CheckExamples`.
+func decodeDefault(td *gentypes.TypeDefinition, data []byte) error {
+	if !td.IsPrimitive() {
+		return nil
+	}
+
+	decoder, ok := defaultDecoders[*td.GoType]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(data, decoder())
+}
+
+// nestedDefaultStatements renders the statements that applies the defaults of the structs
+// within the _field_.
+func nestedDefaultStatements(ctx *GeneratorContext, field *GoField, td *gentypes.TypeDefinition) []string {
+	hasDefaults := func(td *gentypes.TypeDefinition) bool {
+		return td != nil && td.IsObject() && IsNamedType(td)
+	}
+
+	expr := "v." + field.Name
+
+	switch {
+	case hasDefaults(td):
+		switch field.Presence {
+		case PresencePointer:
+			return []string{fmt.Sprintf("if %s != nil {\n%s.ApplyDefaults()\n}", expr, expr)}
+		case PresenceOptional:
+			return []string{fmt.Sprintf("if %s.Present {\n%s.Value.ApplyDefaults()\n}", expr, expr)}
+		case PresenceNullable:
+			return []string{fmt.Sprintf("if %s.Present && !%s.Null {\n%s.Value.ApplyDefaults()\n}", expr, expr, expr)}
+		}
+
+		return []string{expr + ".ApplyDefaults()"}
+	}
+
+	if field.Presence.IsGeneric() {
+		expr += ".Value"
+	}
+
+	switch {
+	case td.IsArray() && hasDefaults(ctx.ResolveDefinition(td.Items)):
+		return []string{fmt.Sprintf("for i := range %s {\n%s[i].ApplyDefaults()\n}", expr, expr)}
+	case td.IsMap() && hasDefaults(ctx.ResolveDefinition(td.AdditionalProperties)):
+		return []string{fmt.Sprintf(
+			"for k, value := range %s {\nvalue.ApplyDefaults()\n%s[k] = value\n}", expr, expr,
+		)}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/iancoleman/strcase"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// ExampleFileName is the name of the file, in each package, that the examples are
+// rendered into.
+const ExampleFileName = "example_test.go"
+
+// SchemaExample is an example value of a schema.
+type SchemaExample struct {
+	// Name is the name of the example. It is empty for unnamed examples.
+	Name string
+	// Value is the example value as unmarshalled from json.
+	Value any
+	// Pointer is the json pointer of the example relative to the schema, e.g.
+	// _/examples/0_. It is empty when composed from the property examples.
+	Pointer string
+}
+
+// SchemaExamples returns the examples of the named type _td_.
+//
+// The _example_ of the schema comes first, followed by the _examples_. The _examples_ is
+// either an array of values or a map of named values, where a value may be an _Example
+// Object_ with a _value_. When the schema has no examples, an object is composed of the
+// _example_ of each inline property, provided that all required properties has an example.
+//
+// NOTE: An _example_ next to a `$ref` is not available since the siblings of a reference
+// are ignored when loaded.
+func SchemaExamples(ctx *GeneratorContext, td *gentypes.TypeDefinition) ([]SchemaExample, error) {
+	examples, err := DeclaredExamples(td)
+	if err != nil || len(examples) > 0 || !td.IsObject() {
+		return examples, err
+	}
+
+	composed := map[string]any{}
+	for _, property := range AllProperties(ctx, td) {
+		if property.Reference == nil && property.Definition != nil && property.Definition.Schema.Example != nil {
+			composed[property.PropertyName] = property.Definition.Schema.Example
+		} else if property.Required {
+			// An incomplete example would not match when printed
+			return examples, nil
+		}
+	}
+
+	if len(composed) > 0 {
+		examples = append(examples, SchemaExample{Value: composed})
+	}
+
+	return examples, nil
+}
+
+// DeclaredExamples returns the _example_ and _examples_ declared in the schema of _td_,
+// see `SchemaExamples`.
+func DeclaredExamples(td *gentypes.TypeDefinition) ([]SchemaExample, error) {
+	examples := []SchemaExample{}
+
+	if td.Schema == nil {
+		return examples, nil
+	}
+
+	if td.Schema.Example != nil {
+		examples = append(examples, SchemaExample{Value: td.Schema.Example, Pointer: "/example"})
+	}
+
+	var values any
+	switch v := td.Schema.Extensions["examples"].(type) {
+	case nil:
+	case json.RawMessage:
+		if err := json.Unmarshal(v, &values); err != nil {
+			return nil, fmt.Errorf("examples is not valid json (%s): %s", td.ID.String(), err.Error())
+		}
+	default:
+		values = v
+	}
+
+	switch v := values.(type) {
+	case nil:
+	case []any:
+		for i, value := range v {
+			examples = append(examples, SchemaExample{Value: value, Pointer: fmt.Sprintf("/examples/%d", i)})
+		}
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := v[name]
+			pointer := "/examples/" + EscapePointerToken(name)
+
+			if object, ok := value.(map[string]any); ok {
+				if inner, ok := object["value"]; ok {
+					value = inner
+					pointer += "/value"
+				}
+			}
+
+			examples = append(examples, SchemaExample{Name: name, Value: value, Pointer: pointer})
+		}
+	default:
+		return nil, fmt.Errorf("examples must be an array or a map of examples (%s)", td.ID.String())
+	}
+
+	return examples, nil
+}
+
+// NewExampleFile creates the examples file of the package that _td_ is declared in.
+func NewExampleFile(td *gentypes.TypeDefinition) *GoFile {
+	file := NewGoFile(td)
+	file.Path = filepath.Join(td.ID.Path, ExampleFileName)
+
+	return file
+}
+
+// DeclareExamples adds an `Example<Type>` function to the examples _file_ for each example
+// of the type _decl_ rendered from _td_. The function unmarshals the example and prints it
+// using `types.CanonicalJSON`, the expected output is the example with sorted properties.
+//
+// An example that does not match the schema, which `CheckExamples` reports, has no expected
+// output since the unmarshalled value would not print as the example. It is compiled but
+// not run by `go test`.
+//
+// The first unnamed example is `Example<Type>`, the following `Example<Type>_example<N>`.
+// Named examples are `Example<Type>_<name>`.
+func DeclareExamples(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	examples, err := SchemaExamples(ctx, td)
+	if err != nil {
+		return err
+	}
+
+	schema := ResolvedSchema(ctx, td)
+
+	for i, example := range examples {
+		data, err := json.Marshal(example.Value)
+		if err != nil {
+			return fmt.Errorf("example %d of %s can not be marshalled: %s", i+1, td.ID.String(), err.Error())
+		}
+
+		name := "Example" + decl.Name
+		doc := fmt.Sprintf("// %s unmarshals the example of %s in the specification.", name, decl.Name)
+
+		switch {
+		case example.Name != "":
+			name += "_" + exampleSuffix(example.Name)
+			doc = fmt.Sprintf("// %s unmarshals the %s example of %s in the specification.", name, example.Name, decl.Name)
+		case i > 0:
+			name += fmt.Sprintf("_example%d", i+1)
+			doc = fmt.Sprintf("// %s unmarshals example %d of %s in the specification.", name, i+1, decl.Name)
+		}
+
+		literal := "`" + string(data) + "`"
+		if strings.Contains(literal[1:len(literal)-1], "`") {
+			literal = strconv.Quote(string(data))
+		}
+
+		output := "\n// Output: " + string(data)
+		if ctx.settings.defaults_on_unmarshal || schema.VisitJSON(example.Value, openapi3.MultiErrors()) != nil {
+			output = ""
+		}
+
+		file.Funcs = append(file.Funcs, fmt.Sprintf(
+			"%s\nfunc %s() {\nvar v %s\nif err := %s([]byte(%s), &v); err != nil {\n%s(err)\nreturn\n}\n\n%s(%s(v))%s\n}",
+			doc, name, decl.Name,
+			file.Qualify("encoding/json", "Unmarshal"), literal,
+			file.Qualify("fmt", "Println"),
+			file.Qualify("fmt", "Println"), file.Qualify(RuntimePackage, "CanonicalJSON"), output,
+		))
+	}
+
+	return nil
+}
+
+// exampleSuffix renders the _name_ of an example as the suffix of an example function,
+// which must start with a lower case letter.
+func exampleSuffix(name string) string {
+	suffix := strcase.ToLowerCamel(name)
+
+	var sb strings.Builder
+	for _, r := range suffix {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+
+	suffix = sb.String()
+	if suffix == "" || suffix[0] < 'a' || suffix[0] > 'z' {
+		return "example" + suffix
+	}
+
+	return suffix
+}
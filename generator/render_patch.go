@@ -0,0 +1,376 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// PatchSuffix is the suffix of the JSON Merge Patch type of a struct.
+const PatchSuffix = "Patch"
+
+// patchField is a field in a patch type together with the field it patches.
+type patchField struct {
+	field    *GoField
+	target   *GoField
+	td       *gentypes.TypeDefinition
+	goType   string
+	nested   bool
+	indirect bool
+	values   string
+}
+
+// DeclarePatch declares the `<Type>Patch` of the struct _td_ when enabled in the settings.
+// It is a JSON Merge Patch (RFC 7396) of _td_ where each property is a `types.Nullable`
+// that is absent when the property is left as is, `null` when it is removed and otherwise
+// the new value.
+//
+// The `Apply` method, that applies a patch, is added to _decl_ and the `Diff<Type>` function,
+// that creates the patch between two values, to the patch.
+//
+// A property of a struct type is patched by the patch of that type and a property of a map
+// type is patched per key, where `null` removes the key. All other properties are replaced.
+//
+// NOTE: The additional properties of _td_ are not patched.
+func DeclarePatch(
+	ctx *GeneratorContext,
+	file *GoFile,
+	td *gentypes.TypeDefinition,
+	decl *GoTypeDecl) (*GoTypeDecl, error) {
+
+	if !ctx.settings.patch_types || decl.Kind != GoKindStruct {
+		return nil, nil
+	}
+
+	file.Import(RuntimePackage)
+	file.Import("encoding/json")
+	file.Import("reflect")
+
+	pdecl := &GoTypeDecl{
+		Name:       decl.Name + PatchSuffix,
+		Kind:       GoKindStruct,
+		Definition: td,
+		Doc: []string{fmt.Sprintf(
+			"%s is a JSON Merge Patch of %s. An absent property is left as is and a `null` property is removed.",
+			decl.Name+PatchSuffix, decl.Name,
+		)},
+	}
+
+	fields := []*patchField{}
+	scratch := &GoTypeDecl{}
+
+	for _, property := range AllProperties(ctx, td) {
+		if err := declareField(ctx, file, property, scratch); err != nil {
+			return nil, err
+		}
+
+		pf := &patchField{target: scratch.Fields[len(scratch.Fields)-1]}
+
+		if pf.td = ctx.ResolveDefinition(&property.ComponentDefinition); pf.td == nil {
+			return nil, fmt.Errorf("could not resolve property: %s", property.ID.String())
+		}
+
+		var err error
+		if pf.goType, err = GoTypeExprOf(ctx, file, pf.td); err != nil {
+			return nil, err
+		}
+
+		patchType := pf.goType
+
+		switch {
+		case pf.td.IsObject() && IsNamedType(pf.td):
+			pf.nested = true
+			pf.indirect = patchReaches(ctx, pf.td, td, map[string]bool{})
+
+			patchType = file.Qualify(pf.td.GoPackage, GoTypeName(pf.td)+PatchSuffix)
+			if pf.indirect {
+				patchType = "*" + patchType
+			}
+		case pf.td.IsMap():
+			if pf.values, err = GoTypeExpr(ctx, file, pf.td.AdditionalProperties); err != nil {
+				return nil, err
+			}
+
+			patchType = "map[string]" + PresenceNullable.Wrap(file, pf.values)
+		}
+
+		pf.field = &GoField{
+			Name:     pf.target.Name,
+			Type:     PresenceNullable.Wrap(file, patchType),
+			Tag:      fmt.Sprintf(`json:"%s,omitempty"`, property.PropertyName),
+			Presence: PresenceNullable,
+			Property: property,
+		}
+
+		pdecl.Fields = append(pdecl.Fields, pf.field)
+		fields = append(fields, pf)
+	}
+
+	validate, err := patchValidateStatements(ctx, file, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	pdecl.Validate = validate
+
+	apply := []string{}
+	diff := []string{}
+
+	for _, pf := range fields {
+		format := "if patch.%s.Present {\nif patch.%s.Null {\n%s\n} else {\n%s\n}\n}"
+		if null := patchNullStatement(file, pf); strings.HasPrefix(null, "return") {
+			format = "if patch.%s.Present {\nif patch.%s.Null {\n%s\n}\n%s\n}"
+		}
+
+		apply = append(apply, fmt.Sprintf(
+			format, pf.field.Name, pf.field.Name, patchNullStatement(file, pf), patchSetStatement(file, pf),
+		))
+
+		diff = append(diff, fmt.Sprintf(
+			"if !reflect.DeepEqual(from.%s, to.%s) {\n%s\n}",
+			pf.field.Name, pf.field.Name, patchDiffStatement(file, pf),
+		))
+	}
+
+	decl.Methods = append(decl.Methods, fmt.Sprintf(
+		"// Apply returns a copy of %s with the JSON Merge Patch applied. It fails when a required\n"+
+			"// property is removed.\n"+
+			"func (v %s) Apply(patch %s) (%s, error) {\nresult := v\n%s\nreturn result, nil\n}",
+		decl.Name, decl.Name, pdecl.Name, decl.Name, joinStatements(apply),
+	))
+
+	pdecl.Methods = append(pdecl.Methods, fmt.Sprintf(
+		"// Diff%s returns the JSON Merge Patch that makes _from_ equal to _to_ when applied.\n"+
+			"func Diff%s(from, to %s) %s {\nvar patch %s\n%s\nreturn patch\n}",
+		decl.Name, decl.Name, decl.Name, pdecl.Name, pdecl.Name, joinStatements(diff),
+	))
+
+	return pdecl, nil
+}
+
+// patchReaches returns `true` when the patch of _from_ contains the patch of _to_, directly
+// or indirectly. Such patch is referenced by pointer since it would otherwise contain itself.
+func patchReaches(ctx *GeneratorContext, from, to *gentypes.TypeDefinition, visited map[string]bool) bool {
+	if from.ID.Equal(&to.ID) {
+		return true
+	}
+
+	if visited[from.ID.String()] {
+		return false
+	}
+
+	visited[from.ID.String()] = true
+
+	for _, property := range AllProperties(ctx, from) {
+		if td := ctx.ResolveDefinition(&property.ComponentDefinition); td != nil && td.IsObject() && IsNamedType(td) {
+			if patchReaches(ctx, td, to, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// patchValue returns the go expression of the value of the _field_ in _expr_ and the
+// condition when it is set, or an empty condition when always set.
+func patchValue(field *GoField, expr string) (string, string) {
+	switch field.Presence {
+	case PresencePointer:
+		return "*" + expr, expr + " != nil"
+	case PresenceOptional:
+		return expr + ".Value", expr + ".Present"
+	case PresenceNullable:
+		return expr + ".Value", expr + ".Present && !" + expr + ".Null"
+	}
+
+	return expr, ""
+}
+
+// patchAssign renders the assignment of _value_ to the _field_ in _expr_.
+func patchAssign(field *GoField, expr, value string) string {
+	switch field.Presence {
+	case PresencePointer:
+		return fmt.Sprintf("value := %s\n%s = &value", value, expr)
+	case PresenceOptional, PresenceNullable:
+		return fmt.Sprintf("%s.Set(%s)", expr, value)
+	}
+
+	return fmt.Sprintf("%s = %s", expr, value)
+}
+
+// patchBase renders the declaration of `base` that holds the current value of the _field_
+// in _expr_ or the zero value when not set.
+func patchBase(field *GoField, expr, goType string) string {
+	value, set := patchValue(field, expr)
+	if set == "" {
+		return "base := " + value
+	}
+
+	return fmt.Sprintf("var base %s\nif %s {\nbase = %s\n}", goType, set, value)
+}
+
+// patchNullStatement renders the statement that removes the patched property.
+func patchNullStatement(file *GoFile, pf *patchField) string {
+	target := pf.target
+	expr := "result." + target.Name
+	path := strconv.Quote(target.Property.PropertyName)
+
+	switch {
+	case target.Presence == PresencePointer, target.Presence == PresenceValue && IsNillable(pf.td):
+		if target.Property.Required && !pf.td.Schema.Nullable {
+			return "return v, " + errorf(file, path, "is required")
+		}
+
+		return expr + " = nil"
+	case target.Presence == PresenceOptional:
+		return fmt.Sprintf("%s = %s{}", expr, target.Type)
+	case target.Presence == PresenceNullable:
+		return expr + ".SetNull()"
+	case !target.Property.Required:
+		// Value where the zero value is absent
+		return expr + " = " + zeroLiteral(pf.td.GoType.Name)
+	}
+
+	return "return v, " + errorf(file, path, "is required")
+}
+
+// patchSetStatement renders the statement that sets the patched property.
+func patchSetStatement(file *GoFile, pf *patchField) string {
+	target := pf.target
+	expr := "result." + target.Name
+	path := strconv.Quote(target.Property.PropertyName)
+
+	switch {
+	case pf.nested:
+		nested := "patch." + pf.field.Name + ".Value"
+		if pf.indirect {
+			// A nil patch is an empty patch
+			nested = fmt.Sprintf(
+				"var nested %s\nif %s != nil {\nnested = *%s\n}",
+				file.Qualify(pf.td.GoPackage, GoTypeName(pf.td)+PatchSuffix), nested, nested,
+			)
+		} else {
+			nested = "nested := " + nested
+		}
+
+		file.Import("fmt")
+
+		return fmt.Sprintf(
+			"%s\n%s\napplied, err := base.Apply(nested)\nif err != nil {\nreturn v, fmt.Errorf(\"%%s: %%w\", %s, err)\n}\n%s",
+			patchBase(target, expr, pf.goType), nested, path, patchAssign(target, expr, "applied"),
+		)
+	case pf.td.IsMap():
+		return fmt.Sprintf(
+			"%s\nmerged := %s{}\nfor k, value := range base {\nmerged[k] = value\n}\n"+
+				"for k, value := range patch.%s.Value {\nif value.Null {\ndelete(merged, k)\n} else if value.Present {\nmerged[k] = value.Value\n}\n}\n%s",
+			patchBase(target, expr, pf.goType), pf.goType, pf.field.Name, patchAssign(target, expr, "merged"),
+		)
+	}
+
+	return patchAssign(target, expr, "patch."+pf.field.Name+".Value")
+}
+
+// patchDiffStatement renders the statement that sets the patch property when the values in
+// `from` and `to` differs.
+func patchDiffStatement(file *GoFile, pf *patchField) string {
+	target := pf.target
+	name := pf.field.Name
+	value, set := patchValue(target, "to."+name)
+
+	if set == "" && !target.Property.Required {
+		set = PresenceCondition(target, pf.td, "to."+name, true)
+	}
+
+	var update string
+
+	switch {
+	case pf.nested:
+		diff := fmt.Sprintf("%s(base, %s)", file.Qualify(pf.td.GoPackage, "Diff"+GoTypeName(pf.td)), value)
+
+		if pf.indirect {
+			update = fmt.Sprintf("%s\ndiff := %s\npatch.%s.Set(&diff)", patchBase(target, "from."+name, pf.goType), diff, name)
+		} else {
+			update = fmt.Sprintf("%s\npatch.%s.Set(%s)", patchBase(target, "from."+name, pf.goType), name, diff)
+		}
+	case pf.td.IsMap():
+		update = fmt.Sprintf(
+			"%s\nvalues := map[string]types.Nullable[%s]{}\n"+
+				"for k := range base {\nif _, ok := %s[k]; !ok {\nvalues[k] = types.NewNull[%s]()\n}\n}\n"+
+				"for k, value := range %s {\nif current, ok := base[k]; !ok || !reflect.DeepEqual(current, value) {\nvalues[k] = types.NewNullable(value)\n}\n}\n"+
+				"patch.%s.Set(values)",
+			patchBase(target, "from."+name, pf.goType), pf.values, value, pf.values, value, name,
+		)
+	default:
+		update = fmt.Sprintf("patch.%s.Set(%s)", name, value)
+	}
+
+	if set == "" {
+		return update
+	}
+
+	return fmt.Sprintf("if %s {\n%s\n} else {\npatch.%s.SetNull()\n}", set, update, name)
+}
+
+// patchValidateStatements renders the statements that validates the values in a patch.
+func patchValidateStatements(ctx *GeneratorContext, file *GoFile, fields []*patchField) ([]string, error) {
+	statements := []string{}
+
+	for _, pf := range fields {
+		expr := "v." + pf.field.Name
+		path := strconv.Quote(pf.field.Property.PropertyName)
+
+		var inner []string
+
+		switch {
+		case pf.nested && pf.indirect:
+			inner = []string{fmt.Sprintf(
+				"if %s.Value != nil {\nif err := %s.Value.Validate(); err != nil {\nreturn fmt.Errorf(\"%%s: %%w\", %s, err)\n}\n}",
+				expr, expr, path,
+			)}
+
+			file.Import("fmt")
+		case pf.nested:
+			inner = []string{fmt.Sprintf(
+				"if err := %s.Value.Validate(); err != nil {\nreturn fmt.Errorf(\"%%s: %%w\", %s, err)\n}",
+				expr, path,
+			)}
+
+			file.Import("fmt")
+		case pf.td.IsMap():
+			values := ctx.ResolveDefinition(pf.td.AdditionalProperties)
+			if values == nil {
+				return nil, fmt.Errorf("could not resolve additional properties: %s", pf.td.ID.String())
+			}
+
+			validate, err := ValueStatements(ctx, file, expr+".Value[k0].Value", elementPath(file, path, "[%q]", "k0"), values, 1)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(validate) > 0 {
+				inner = []string{fmt.Sprintf(
+					"for k0 := range %s.Value {\nif %s.Value[k0].Present && !%s.Value[k0].Null {\n%s\n}\n}",
+					expr, expr, expr, joinStatements(validate),
+				)}
+			}
+		default:
+			validate, err := ValueStatements(ctx, file, expr+".Value", path, pf.td, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			inner = validate
+		}
+
+		if len(inner) > 0 {
+			statements = append(statements, fmt.Sprintf(
+				"if %s.Present && !%s.Null {\n%s\n}", expr, expr, joinStatements(inner),
+			))
+		}
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// RoundTripFileName is the name of the file, in each package, that the round-trip and fuzz
+// tests are rendered into.
+const RoundTripFileName = "roundtrip_test.go"
+
+// ValueFaker produces random, but valid, json values, as unmarshalled into `any`, of a type
+// definition. See package _generator/fake_ for an implementation.
+type ValueFaker interface {
+	Value(td *gentypes.TypeDefinition) (any, error)
+}
+
+// NewRoundTripFile creates the round-trip test file of the package that _td_ is declared in.
+func NewRoundTripFile(td *gentypes.TypeDefinition) *GoFile {
+	file := NewGoFile(td)
+	file.Path = filepath.Join(td.ID.Path, RoundTripFileName)
+
+	return file
+}
+
+// RoundTripValues returns the json values of _td_ that the round-trip tests are seeded with.
+// These are the examples of the schema that match the schema, see `SchemaExamples`, followed
+// by the values produced by the _faker_ when not `nil`.
+//
+// A faker that fails to produce a value is added as a diagnostic, e.g. when required
+// properties are recursive, and the fake values of the type are skipped.
+func RoundTripValues(ctx *GeneratorContext, faker ValueFaker, td *gentypes.TypeDefinition) ([]string, error) {
+	examples, err := SchemaExamples(ctx, td)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := ResolvedSchema(ctx, td)
+	values := []string{}
+
+	add := func(value any) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("round-trip value of %s can not be marshalled: %s", td.ID.String(), err.Error())
+		}
+
+		values = append(values, string(data))
+		return nil
+	}
+
+	for _, example := range examples {
+		// Invalid examples are reported by `CheckExamples`
+		if schema.VisitJSON(example.Value) == nil {
+			if err := add(example.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if faker == nil {
+		return values, nil
+	}
+
+	for i := 0; i < ctx.settings.round_trip_fakes; i++ {
+		value, err := faker.Value(td)
+		if err != nil {
+			ctx.AddDiagnostic(&td.ID, "no fake values in round-trip tests: %s", err.Error())
+			break
+		}
+
+		if err := add(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// DeclareRoundTrips adds a `TestRoundTrip<Type>` and a `FuzzUnmarshal<Type>` function to the
+// round-trip _file_ for the type _decl_ rendered from _td_, see `RoundTripValues`.
+//
+// The test unmarshals and validates each value and verifies that marshalling is stable, i.e.
+// unmarshalling the marshalled value and marshalling it again renders the same json. The fuzz
+// target, seeded with the same values, verifies that unmarshal, validate and marshal never
+// panics and that a value that unmarshals also marshals.
+func DeclareRoundTrips(ctx *GeneratorContext, faker ValueFaker, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	values, err := RoundTripValues(ctx, faker, td)
+	if err != nil {
+		return err
+	}
+
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literals = append(literals, "\n"+roundTripLiteral(value)+",")
+	}
+
+	seeds := "roundTrip" + decl.Name
+	file.AddVar(fmt.Sprintf(
+		"// %s are the json values of %s that the round-trip tests are seeded with.\nvar %s = []string{%s\n}",
+		seeds, decl.Name, seeds, strings.Join(literals, ""),
+	))
+
+	validate := ""
+	if HasValidateMethod(td) {
+		validate = "\nif err := v.Validate(); err != nil {\nt.Fatalf(\"%s: %v\", data, err)\n}\n"
+	}
+
+	unmarshal := file.Qualify("encoding/json", "Unmarshal")
+	marshal := file.Qualify("encoding/json", "Marshal")
+	testing := file.Qualify("testing", "T")
+
+	file.Funcs = append(file.Funcs, fmt.Sprintf(
+		"// TestRoundTrip%[1]s verifies that the json of %[1]s is stable when unmarshalled and\n"+
+			"// marshalled again.\n"+
+			"func TestRoundTrip%[1]s(t *%[5]s) {\n"+
+			"for _, data := range %[2]s {\n"+
+			"var v %[1]s\n"+
+			"if err := %[3]s([]byte(data), &v); err != nil {\nt.Fatalf(\"%%s: %%v\", data, err)\n}\n"+
+			"%[6]s\n"+
+			"first, err := %[4]s(v)\nif err != nil {\nt.Fatalf(\"%%s: %%v\", data, err)\n}\n\n"+
+			"var w %[1]s\n"+
+			"if err := %[3]s(first, &w); err != nil {\nt.Fatalf(\"%%s: %%v\", first, err)\n}\n\n"+
+			"second, err := %[4]s(w)\nif err != nil {\nt.Fatalf(\"%%s: %%v\", first, err)\n}\n\n"+
+			"if string(first) != string(second) {\nt.Fatalf(\"not stable: %%s != %%s\", first, second)\n}\n"+
+			"}\n}",
+		decl.Name, seeds, unmarshal, marshal, testing, validate,
+	))
+
+	file.Funcs = append(file.Funcs, fmt.Sprintf(
+		"// FuzzUnmarshal%[1]s verifies that unmarshal, validate and marshal of %[1]s never panics.\n"+
+			"func FuzzUnmarshal%[1]s(f *%[5]s) {\n"+
+			"for _, data := range %[2]s {\nf.Add([]byte(data))\n}\n\n"+
+			"f.Fuzz(func(t *%[6]s, data []byte) {\n"+
+			"var v %[1]s\n"+
+			"if err := %[3]s(data, &v); err != nil {\nreturn\n}\n\n"+
+			"%[7]s"+
+			"if _, err := %[4]s(v); err != nil {\nt.Fatalf(\"%%s: %%v\", data, err)\n}\n"+
+			"})\n}",
+		decl.Name, seeds, unmarshal, marshal,
+		file.Qualify("testing", "F"), testing, fuzzValidate(td),
+	))
+
+	return nil
+}
+
+// fuzzValidate renders the call of `Validate` in the fuzz target of _td_, if any.
+func fuzzValidate(td *gentypes.TypeDefinition) string {
+	if !HasValidateMethod(td) {
+		return ""
+	}
+
+	return "_ = v.Validate()\n\n"
+}
+
+// roundTripLiteral renders the json _value_ as a go string literal.
+func roundTripLiteral(value string) string {
+	if strings.Contains(value, "`") {
+		return strconv.Quote(value)
+	}
+
+	return "`" + value + "`"
+}
@@ -0,0 +1,673 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// ExtensionGoName overrides the name of a go type or struct field, e.g. `x-go-name: ID`.
+const ExtensionGoName = "x-go-name"
+
+// GoTypeKind is the kind of go type declaration to render.
+type GoTypeKind string
+
+const (
+	// GoKindStruct is a struct with fields and embedded compositions.
+	GoKindStruct GoTypeKind = "struct"
+	// GoKindNamed is a named type of an array, map or primitive e.g. `type Usage []UsageType`.
+	GoKindNamed GoTypeKind = "named"
+	// GoKindAlias is a type alias of a type without a schema e.g. `type Anything = any`.
+	GoKindAlias GoTypeKind = "alias"
+	// GoKindEnum is a named primitive with a set of constants.
+	GoKindEnum GoTypeKind = "enum"
+	// GoKindUnion is a struct with one pointer per possible type that is selected by a discriminator.
+	GoKindUnion GoTypeKind = "union"
+)
+
+// GoTypeDecl is the model of a go type declaration when rendered.
+type GoTypeDecl struct {
+	// Name is the go type name.
+	Name string
+	// Doc is the comment lines (without the comment prefix).
+	Doc []string
+	// Kind is the kind of declaration.
+	Kind GoTypeKind
+	// Underlying is the underlying go type for named, alias and enum kinds.
+	Underlying string
+	// Fields are the fields of a struct.
+	Fields []*GoField
+	// EnumValues are the constants of an enum.
+	EnumValues []GoEnumValue
+	// Variants are the types of a union.
+	Variants []GoUnionVariant
+	// Discriminator is the json property name that selects the variant of a union. When
+	// empty, the variants are selected by the ones that unmarshal and validate.
+	Discriminator string
+	// Exclusive is set when exactly one variant of a union must be set (_oneOf_), otherwise
+	// at least one must be set (_anyOf_).
+	Exclusive bool
+	// AdditionalProperties is the go type of the additional properties values of a
+	// struct. When empty, the struct has no additional properties.
+	AdditionalProperties string
+	// KnownProperties are the json names of all declared properties, including those of
+	// embedded compositions. This is used to separate the additional properties.
+	KnownProperties []string
+	// DefaultsOnUnmarshal is set when a struct applies the defaults when unmarshalled
+	// from json.
+	DefaultsOnUnmarshal bool
+	// Validate is the statements of the `Validate` method, see `HasValidate`.
+	Validate []string
+	// Methods are additional go source blocks that are rendered after the type.
+	Methods []string
+	// Definition is the definition the declaration is rendered from.
+	Definition *gentypes.TypeDefinition
+}
+
+// HasEmbedded returns `true` when the struct embeds _allOf_ compositions. Such struct
+// merges the json of the embedded types with its own properties.
+func (decl *GoTypeDecl) HasEmbedded() bool {
+	for _, field := range decl.Fields {
+		if field.Embedded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnsetFields returns the struct fields that are a `types.Optional` or `types.Nullable`.
+// Those are omitted when marshalled and absent, see `types.OmitUnset`.
+func (decl *GoTypeDecl) UnsetFields() []*GoField {
+	fields := []*GoField{}
+	for _, field := range decl.PropertyFields() {
+		if field.Presence.IsGeneric() {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// PropertyFields returns the struct fields that are rendered from properties.
+func (decl *GoTypeDecl) PropertyFields() []*GoField {
+	fields := []*GoField{}
+	for _, field := range decl.Fields {
+		if field.Property != nil {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// HasValidate returns `true` when a `Validate` method is rendered.
+func (decl *GoTypeDecl) HasValidate() bool {
+	return decl.Kind != GoKindAlias
+}
+
+// GoField is a field in a struct.
+type GoField struct {
+	// Name is the go field name. It is empty when _Embedded_.
+	Name string
+	// Type is the go type of the field.
+	Type string
+	// Tag is the complete struct tag without the back ticks.
+	Tag string
+	// Doc is the comment lines (without the comment prefix).
+	Doc []string
+	// Embedded is set when the type is embedded.
+	Embedded bool
+	// Presence is how the field represents an absent or `null` property.
+	Presence Presence
+	// Property is the property that the field is rendered from. It is `nil`
+	// for embedded compositions.
+	Property *gentypes.Property
+}
+
+// GoEnumValue is a constant in a enum.
+type GoEnumValue struct {
+	// Name is the constant name.
+	Name string
+	// Value is the go literal of the value.
+	Value string
+}
+
+// GoUnionVariant is one of the types that a union may hold.
+type GoUnionVariant struct {
+	// Name is the field name holding the variant.
+	Name string
+	// Type is the (qualified) go type of the variant.
+	Type string
+	// MapFrom is the discriminator value that selects the variant.
+	MapFrom string
+	// Validate is the statements that validates the variant when named `value`. It is
+	// used to select variants when there is no discriminator.
+	Validate []string
+}
+
+// builtinGoTypes are the go types that can be used as underlying type of a named
+// type without loosing any behavior such as marshalling.
+var builtinGoTypes = []string{
+	"string", "bool", "byte", "rune", "[]byte",
+	"int", "int8", "int16", "int32", "int64",
+	"uint", "uint8", "uint16", "uint32", "uint64",
+	"float32", "float64",
+}
+
+// textGoTypes are the mapped go types that implements `encoding.TextMarshaler` and
+// `encoding.TextUnmarshaler`.
+var textGoTypes = []string{"time.Time", "types.Date", "types.UUID"}
+
+// declareDelegation adds the methods to the named primitive _decl_, of a mapped go type that
+// is not builtin, that marshals and unmarshals it as the underlying go type. The methods of
+// the underlying type are not part of a defined type, e.g. `type Timestamp time.Time`.
+func declareDelegation(file *GoFile, decl *GoTypeDecl) {
+	file.Import("encoding/json")
+
+	decl.Methods = append(decl.Methods,
+		fmt.Sprintf(
+			"// MarshalJSON marshals the %s as a %s.\nfunc (v %s) MarshalJSON() ([]byte, error) {\nreturn json.Marshal(%s(v))\n}",
+			decl.Name, decl.Underlying, decl.Name, decl.Underlying,
+		),
+		fmt.Sprintf(
+			"// UnmarshalJSON unmarshals the %s as a %s.\nfunc (v *%s) UnmarshalJSON(data []byte) error {\nreturn json.Unmarshal(data, (*%s)(v))\n}",
+			decl.Name, decl.Underlying, decl.Name, decl.Underlying,
+		),
+	)
+
+	if !ContainsString(textGoTypes, decl.Underlying) {
+		return
+	}
+
+	decl.Methods = append(decl.Methods,
+		fmt.Sprintf(
+			"// MarshalText marshals the %s as a %s.\nfunc (v %s) MarshalText() ([]byte, error) {\nreturn %s(v).MarshalText()\n}",
+			decl.Name, decl.Underlying, decl.Name, decl.Underlying,
+		),
+		fmt.Sprintf(
+			"// UnmarshalText unmarshals the %s as a %s.\nfunc (v *%s) UnmarshalText(data []byte) error {\nreturn (*%s)(v).UnmarshalText(data)\n}",
+			decl.Name, decl.Underlying, decl.Name, decl.Underlying,
+		),
+	)
+}
+
+// nillableGoTypes are the mapped go types that do not need a pointer to be optional.
+var nillableGoTypes = []string{"[]byte", "io.Reader", "any", "interface{}", "json.RawMessage"}
+
+// IsBuiltinGoType returns `true` if _name_ is a predeclared go type (or a byte slice).
+func IsBuiltinGoType(name string) bool {
+	return ContainsString(builtinGoTypes, name)
+}
+
+// IsNumericGoType returns `true` if _name_ is a predeclared go integer or float type.
+func IsNumericGoType(name string) bool {
+	return IsBuiltinGoType(name) && name != "string" && name != "bool" && name != "[]byte"
+}
+
+// IsIntegerGoType returns `true` if _name_ is a predeclared go integer type.
+func IsIntegerGoType(name string) bool {
+	return IsNumericGoType(name) && !strings.HasPrefix(name, "float")
+}
+
+// initialisms are words that are rendered in upper case in go names.
+var initialisms = []string{
+	"acl", "api", "ascii", "cpu", "css", "dns", "eof", "guid", "html", "http", "https",
+	"id", "ip", "json", "lhs", "qps", "ram", "rhs", "rpc", "sla", "smtp", "sql", "ssh",
+	"tcp", "tls", "ttl", "udp", "ui", "uid", "uri", "url", "utf8", "uuid", "vm", "xml",
+}
+
+// GoName renders _name_ as an exported go name in camel case where common initialisms
+// are rendered in upper case, e.g. "callUri" becomes "CallURI".
+func GoName(name string) string {
+	result := ""
+
+	for _, word := range strings.Split(strcase.ToSnake(name), "_") {
+		if ContainsString(initialisms, word) {
+			result += strings.ToUpper(word)
+		} else {
+			result += strcase.ToCamel(word)
+		}
+	}
+
+	return result
+}
+
+// GoTypeName returns the go type name of _td_. It is the camel cased type name of the
+// id unless overridden by the `x-go-name` extension on a named schema.
+func GoTypeName(td *gentypes.TypeDefinition) string {
+	if !td.Inline {
+		if name, ok := GetExtensionString(td.Schema, ExtensionGoName); ok && name != "" {
+			return name
+		}
+	}
+
+	return GoName(td.ID.TypeName)
+}
+
+// GoFieldName returns the go field name of the _property_. The `x-go-name` extension
+// on a inline property overrides the camel cased property name.
+func GoFieldName(property *gentypes.Property) string {
+	if property.Definition != nil && property.Reference == nil {
+		if name, ok := GetExtensionString(property.Definition.Schema, ExtensionGoName); ok && name != "" {
+			return name
+		}
+	}
+
+	name := GoName(property.PropertyName)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// GoTypeExpr returns the go type expression for the _component_ as seen from _file_. The
+// needed imports are added to the _file_.
+func GoTypeExpr(ctx *GeneratorContext, file *GoFile, component *gentypes.ComponentDefinition) (string, error) {
+	td := ctx.ResolveDefinition(component)
+	if td == nil {
+		return "", fmt.Errorf("could not resolve component: %s", component.ID.String())
+	}
+
+	return GoTypeExprOf(ctx, file, td)
+}
+
+// GoTypeExprOf returns the go type expression for _td_ as seen from _file_.
+func GoTypeExprOf(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition) (string, error) {
+	if IsNamedType(td) {
+		return file.Qualify(td.GoPackage, GoTypeName(td)), nil
+	}
+
+	switch {
+	case td.IsArray():
+		items, err := GoTypeExpr(ctx, file, td.Items)
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + items, nil
+	case td.IsMap():
+		values, err := GoTypeExpr(ctx, file, td.AdditionalProperties)
+		if err != nil {
+			return "", err
+		}
+
+		return "map[string]" + values, nil
+	case td.IsPrimitive():
+		file.Import(td.GoType.Import)
+		return td.GoType.Name, nil
+	}
+
+	return "any", nil
+}
+
+// IsNillable returns `true` if the go type of _td_ can represent absence without
+// using a pointer.
+func IsNillable(td *gentypes.TypeDefinition) bool {
+	switch {
+	case td.IsArray(), td.IsMap():
+		return true
+	case td.IsPrimitive():
+		return ContainsString(nillableGoTypes, td.GoType.Name)
+	case td.IsObject(), td.IsUnion():
+		return false
+	}
+
+	return true
+}
+
+// DeclareGoType creates the declaration of the named type _td_ within _file_.
+func DeclareGoType(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition) (*GoTypeDecl, error) {
+	decl := &GoTypeDecl{
+		Name:       GoTypeName(td),
+		Definition: td,
+	}
+
+	decl.Doc = TypeDoc(td, decl.Name)
+
+	var err error
+
+	switch {
+	case td.IsUnion():
+		err = declareUnion(ctx, file, td, decl)
+	case td.IsObject():
+		err = declareStruct(ctx, file, td, decl)
+	case td.IsEnum():
+		err = declareEnum(ctx, file, td, decl)
+	case td.IsArray(), td.IsMap():
+		decl.Kind = GoKindNamed
+		decl.Underlying, err = goUnderlyingExpr(ctx, file, td)
+	case td.IsPrimitive() && IsBuiltinGoType(td.GoType.Name):
+		decl.Kind = GoKindNamed
+		decl.Underlying = td.GoType.Name
+	case td.IsPrimitive():
+		decl.Kind = GoKindNamed
+		decl.Underlying = td.GoType.Name
+		file.Import(td.GoType.Import)
+		declareDelegation(file, decl)
+	default:
+		decl.Kind = GoKindAlias
+		decl.Underlying = "any"
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if decl.HasValidate() {
+		if decl.Validate, err = ValidateStatements(ctx, file, decl); err != nil {
+			return nil, err
+		}
+	}
+
+	return decl, nil
+}
+
+// goUnderlyingExpr returns the underlying type of a named array or map.
+func goUnderlyingExpr(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition) (string, error) {
+	if td.IsArray() {
+		items, err := GoTypeExpr(ctx, file, td.Items)
+		return "[]" + items, err
+	}
+
+	values, err := GoTypeExpr(ctx, file, td.AdditionalProperties)
+	return "map[string]" + values, err
+}
+
+func declareStruct(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	decl.Kind = GoKindStruct
+
+	if err := declareCompositions(ctx, file, td, decl); err != nil {
+		return err
+	}
+
+	for i := range td.Properties {
+		if err := declareField(ctx, file, &td.Properties[i], decl); err != nil {
+			return err
+		}
+	}
+
+	if decl.HasEmbedded() {
+		file.Import(RuntimePackage)
+	}
+
+	if ctx.settings.xml {
+		declareXMLName(file, td, decl)
+	}
+
+	if len(decl.UnsetFields()) > 0 {
+		file.Import("encoding/json")
+	}
+
+	if td.AdditionalProperties != nil {
+		values, err := GoTypeExpr(ctx, file, td.AdditionalProperties)
+		if err != nil {
+			return err
+		}
+
+		decl.AdditionalProperties = values
+		decl.KnownProperties = JSONPropertyNames(ctx, td)
+
+		decl.Fields = append(decl.Fields, &GoField{
+			Name: AdditionalPropertiesField,
+			Type: "map[string]" + values,
+			Tag:  xmlIgnore(ctx, `json:"-"`),
+			Doc:  []string{"AdditionalProperties holds all properties that are not declared."},
+		})
+
+		file.Import("encoding/json")
+	}
+
+	return declareDefaults(ctx, file, td, decl)
+}
+
+// declareCompositions embeds the _allOf_ members of _td_ in the struct, or flattens their
+// fields into it when the composition is inline.
+func declareCompositions(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	for i := range td.Composition {
+		composed := ctx.ResolveDefinition(&td.Composition[i].ComponentDefinition)
+		if composed == nil {
+			return fmt.Errorf("could not resolve composition: %s", td.Composition[i].ID.String())
+		}
+
+		if td.Composition[i].Inline {
+			if err := declareCompositions(ctx, file, composed, decl); err != nil {
+				return err
+			}
+
+			for j := range composed.Properties {
+				if err := declareField(ctx, file, &composed.Properties[j], decl); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		decl.Fields = append(decl.Fields, &GoField{
+			Name:     GoTypeName(composed),
+			Type:     file.Qualify(composed.GoPackage, GoTypeName(composed)),
+			Embedded: true,
+		})
+	}
+
+	return nil
+}
+
+// declareField adds the field for the _property_. If a field already exists for the
+// property, e.g. when flattened from several compositions, the last declaration wins.
+func declareField(ctx *GeneratorContext, file *GoFile, property *gentypes.Property, decl *GoTypeDecl) error {
+	property_td := ctx.ResolveDefinition(&property.ComponentDefinition)
+	if property_td == nil {
+		return fmt.Errorf("could not resolve property: %s", property.ID.String())
+	}
+
+	goType, err := GoTypeExprOf(ctx, file, property_td)
+	if err != nil {
+		return err
+	}
+
+	field := &GoField{
+		Name:     GoFieldName(property),
+		Type:     goType,
+		Doc:      FieldDoc(property, property_td),
+		Property: property,
+	}
+
+	if field.Presence, err = PropertyPresence(ctx, property, property_td); err != nil {
+		return err
+	}
+
+	field.Type = field.Presence.Wrap(file, goType)
+
+	if field.Tag, err = FieldTag(ctx, property, property_td, field); err != nil {
+		return err
+	}
+
+	for i := range decl.Fields {
+		if decl.Fields[i].Property != nil && decl.Fields[i].Property.PropertyName == property.PropertyName {
+			decl.Fields[i] = field
+			return nil
+		}
+	}
+
+	decl.Fields = append(decl.Fields, field)
+	return nil
+}
+
+// AdditionalPropertiesField is the name of the struct field that holds the additional properties.
+const AdditionalPropertiesField = "AdditionalProperties"
+
+// JSONPropertyNames returns the names of all properties of _td_ including the properties of
+// the compositions, sorted by name.
+func JSONPropertyNames(ctx *GeneratorContext, td *gentypes.TypeDefinition) []string {
+	names := []string{}
+
+	for i := range td.Composition {
+		if composed := ctx.ResolveDefinition(&td.Composition[i].ComponentDefinition); composed != nil {
+			for _, name := range JSONPropertyNames(ctx, composed) {
+				if !ContainsString(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	for i := range td.Properties {
+		if !ContainsString(names, td.Properties[i].PropertyName) {
+			names = append(names, td.Properties[i].PropertyName)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func declareEnum(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	decl.Kind = GoKindEnum
+	decl.Underlying = td.GoType.Name
+
+	if !IsBuiltinGoType(td.GoType.Name) {
+		return fmt.Errorf("enum must be of a builtin go type: %s (%s)", td.GoType.Name, td.ID.String())
+	}
+
+	names := map[string]bool{}
+	literals := map[string]bool{}
+
+	for i, value := range td.Schema.Enum {
+		if value == nil {
+			continue
+		}
+
+		literal, err := GoLiteral(td.GoType.Name, value)
+		if err != nil {
+			return fmt.Errorf("enum %s: %s", td.ID.String(), err.Error())
+		}
+
+		if literals[literal] {
+			// Same go value, e.g. 1 and 1.0, would be a duplicate case
+			continue
+		}
+
+		name := decl.Name + GoName(fmt.Sprintf("%v", value))
+		if name == decl.Name {
+			name = fmt.Sprintf("%sValue%d", decl.Name, i)
+		}
+
+		// Values such as "a-b" and "a_b" have the same go name
+		unique := name
+		for n := 2; names[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", name, n)
+		}
+
+		names[unique] = true
+		literals[literal] = true
+
+		decl.EnumValues = append(decl.EnumValues, GoEnumValue{Name: unique, Value: literal})
+	}
+
+	return nil
+}
+
+func declareUnion(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) error {
+	decl.Kind = GoKindUnion
+	decl.Exclusive = len(td.AnyOf) == 0
+
+	file.Import("encoding/json")
+	file.Import("fmt")
+
+	for i := range td.DiscriminatorComponents {
+		dc := &td.DiscriminatorComponents[i]
+
+		variant := ctx.ResolveDefinition(&dc.ComponentDefinition)
+		if variant == nil {
+			return fmt.Errorf("could not resolve discriminator component: %s", dc.ID.String())
+		}
+
+		decl.Discriminator = dc.Discriminator
+		decl.Variants = append(decl.Variants, GoUnionVariant{
+			Name:    GoTypeName(variant),
+			Type:    file.Qualify(variant.GoPackage, GoTypeName(variant)),
+			MapFrom: dc.MapFrom,
+		})
+	}
+
+	if decl.Discriminator != "" {
+		return nil
+	}
+
+	members := td.OneOf
+	if !decl.Exclusive {
+		members = td.AnyOf
+	}
+
+	for i := range members {
+		variant := ctx.ResolveDefinition(&members[i])
+		if variant == nil {
+			return fmt.Errorf("could not resolve union member: %s", members[i].ID.String())
+		}
+
+		goType, err := GoTypeExprOf(ctx, file, variant)
+		if err != nil {
+			return err
+		}
+
+		name := GoTypeName(variant)
+		if variant.Inline {
+			name = GoName(variant.ID.TypeName[strings.LastIndex(variant.ID.TypeName, "_")+1:])
+		}
+
+		validate, err := ValueStatements(ctx, file, "value", "", variant, 0)
+		if err != nil {
+			return err
+		}
+
+		decl.Variants = append(decl.Variants, GoUnionVariant{
+			Name:     name,
+			Type:     goType,
+			Validate: validate,
+		})
+	}
+
+	file.Import(RuntimePackage)
+	return nil
+}
+
+// GoLiteral renders the _value_ (as decoded from json) as a go literal of _goType_.
+func GoLiteral(goType string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		if goType != "string" {
+			return "", fmt.Errorf("string value '%s' for go type %s", v, goType)
+		}
+
+		return strconv.Quote(v), nil
+	case bool:
+		if goType != "bool" {
+			return "", fmt.Errorf("bool value '%v' for go type %s", v, goType)
+		}
+
+		return strconv.FormatBool(v), nil
+	case float64:
+		if !IsNumericGoType(goType) {
+			return "", fmt.Errorf("number value '%v' for go type %s", v, goType)
+		}
+
+		if !strings.HasPrefix(goType, "float") && v != float64(int64(v)) {
+			return "", fmt.Errorf("number value '%v' is not an integer (%s)", v, goType)
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return GoLiteral(goType, float64(v))
+	case int64:
+		return GoLiteral(goType, float64(v))
+	}
+
+	return "", fmt.Errorf("unsupported value '%v' (%T) for go type %s", value, value, goType)
+}
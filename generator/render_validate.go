@@ -0,0 +1,513 @@
+package generator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// HasValidateMethod returns `true` if the rendered go type of _td_ has a `Validate` method.
+//
+// All named types except aliases, e.g. `type Anything = any`, have a `Validate` method.
+func HasValidateMethod(td *gentypes.TypeDefinition) bool {
+	if !IsNamedType(td) {
+		return false
+	}
+
+	switch {
+	case td.IsUnion(), td.IsObject(), td.IsEnum(), td.IsArray(), td.IsMap(), td.IsPrimitive():
+		return true
+	}
+
+	return false
+}
+
+// ValidateStatements renders the statements of the `Validate` method of _decl_. The
+// receiver is named `v`.
+func ValidateStatements(ctx *GeneratorContext, file *GoFile, decl *GoTypeDecl) ([]string, error) {
+	td := decl.Definition
+
+	switch decl.Kind {
+	case GoKindUnion, GoKindStruct:
+		validate := validateStruct
+		if decl.Kind == GoKindUnion {
+			validate = validateUnion
+		}
+
+		statements, err := validate(ctx, file, decl)
+		if err != nil {
+			return nil, err
+		}
+
+		not, err := NotStatements(ctx, file, "v", "", td, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(statements, not...), nil
+	}
+
+	statements := []string{}
+
+	if decl.Kind == GoKindEnum {
+		statements = append(statements, validateEnum(file, decl)...)
+	}
+
+	constraints, err := ConstraintStatements(ctx, file, "v", "", td, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(statements, constraints...), nil
+}
+
+func validateStruct(ctx *GeneratorContext, file *GoFile, decl *GoTypeDecl) ([]string, error) {
+	statements := []string{}
+
+	for _, field := range decl.Fields {
+		if field.Embedded {
+			statements = append(statements, fmt.Sprintf(
+				"if err := v.%s.Validate(); err != nil {\nreturn err\n}", field.Name,
+			))
+
+			continue
+		}
+
+		if field.Property == nil {
+			// Not a property e.g. additional properties
+			continue
+		}
+
+		property_td := ctx.ResolveDefinition(&field.Property.ComponentDefinition)
+		expr := "v." + field.Name
+		path := strconv.Quote(field.Property.PropertyName)
+
+		if field.Property.Required {
+			switch {
+			case field.Presence.IsGeneric():
+				statements = append(statements, fmt.Sprintf(
+					"if !%s.Present {\nreturn %s\n}", expr, errorf(file, path, "is required"),
+				))
+			case !property_td.Schema.Nullable &&
+				(field.Presence == PresencePointer || property_td.IsArray() || property_td.IsMap()):
+				statements = append(statements, fmt.Sprintf(
+					"if %s == nil {\nreturn %s\n}", expr, errorf(file, path, "is required"),
+				))
+			}
+		}
+
+		value, guard := expr, ""
+
+		switch field.Presence {
+		case PresencePointer:
+			value, guard = "(*"+expr+")", expr+" != nil"
+		case PresenceOptional:
+			value, guard = expr+".Value", expr+".Present"
+		case PresenceNullable:
+			value, guard = expr+".Value", expr+".Present && !"+expr+".Null"
+		default:
+			switch {
+			case field.Property.Required:
+			case IsNillable(property_td):
+				// Absent when omitted, e.g. an optional array with _minItems_
+				guard = expr + " != nil"
+			default:
+				// The zero value is absent
+				guard = PresenceCondition(field, property_td, expr, true)
+			}
+		}
+
+		inner, err := ValueStatements(ctx, file, value, path, property_td, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if guard != "" && len(inner) > 0 {
+			statements = append(statements, fmt.Sprintf("if %s {\n%s\n}", guard, joinStatements(inner)))
+			continue
+		}
+
+		statements = append(statements, inner...)
+	}
+
+	if decl.AdditionalProperties != "" {
+		values := ctx.ResolveDefinition(decl.Definition.AdditionalProperties)
+		if values == nil {
+			return nil, fmt.Errorf("could not resolve additional properties: %s", decl.Definition.ID.String())
+		}
+
+		expr := "v." + AdditionalPropertiesField
+
+		inner, err := ValueStatements(ctx, file, expr+"[k0]", elementPath(file, "", "%s", "k0"), values, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(inner) > 0 {
+			statements = append(statements, fmt.Sprintf(
+				"for k0 := range %s {\n%s\n}", expr, joinStatements(inner),
+			))
+		}
+	}
+
+	return statements, nil
+}
+
+func validateUnion(ctx *GeneratorContext, file *GoFile, decl *GoTypeDecl) ([]string, error) {
+	statements := []string{"count := 0"}
+
+	for _, variant := range decl.Variants {
+		validate := variant.Validate
+		if decl.Discriminator != "" {
+			validate = []string{fmt.Sprintf("if err := v.%s.Validate(); err != nil {\nreturn err\n}", variant.Name)}
+		} else if len(validate) > 0 {
+			validate = append([]string{fmt.Sprintf("value := *v.%s", variant.Name)}, validate...)
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"if v.%s != nil {\n%s\n}", variant.Name, joinStatements(append([]string{"count++"}, validate...)),
+		))
+	}
+
+	if !decl.Exclusive {
+		return append(statements, fmt.Sprintf(
+			"if count == 0 {\nreturn %s\n}", errorf(file, "", "at least one value must be set"),
+		)), nil
+	}
+
+	statements = append(statements, fmt.Sprintf(
+		"if count != 1 {\nreturn %s\n}",
+		errorf(file, "", "exactly one value must be set, got %d", "count"),
+	))
+
+	return statements, nil
+}
+
+// validateEnum renders the check that the value is one of the enum values. An enum that
+// only allows `null` has no values and any value of the go type is accepted.
+func validateEnum(file *GoFile, decl *GoTypeDecl) []string {
+	if len(decl.EnumValues) == 0 {
+		return nil
+	}
+
+	names := ""
+	for i, value := range decl.EnumValues {
+		if i > 0 {
+			names += ", "
+		}
+
+		names += value.Name
+	}
+
+	return []string{fmt.Sprintf(
+		"switch v {\ncase %s:\ndefault:\nreturn %s\n}",
+		names, errorf(file, "", "invalid value: %v", "v"),
+	)}
+}
+
+// ValueStatements renders the statements that validates the go expression _expr_ of
+// type _td_. If the type has a `Validate` method it is invoked, otherwise the constraints
+// are validated in place.
+//
+// The _path_ is a go expression that renders the path of the value in error messages. When
+// empty, no path is rendered. The _depth_ is the nesting depth of loops.
+func ValueStatements(
+	ctx *GeneratorContext,
+	file *GoFile,
+	expr, path string,
+	td *gentypes.TypeDefinition,
+	depth int) ([]string, error) {
+
+	if HasValidateMethod(td) {
+		if path == "" {
+			return []string{fmt.Sprintf("if err := %s.Validate(); err != nil {\nreturn err\n}", expr)}, nil
+		}
+
+		file.Import("fmt")
+
+		return []string{fmt.Sprintf(
+			"if err := %s.Validate(); err != nil {\nreturn fmt.Errorf(\"%%s: %%w\", %s, err)\n}", expr, path,
+		)}, nil
+	}
+
+	if IsNamedType(td) {
+		// Alias -> nothing to validate
+		return nil, nil
+	}
+
+	return ConstraintStatements(ctx, file, expr, path, td, depth)
+}
+
+// ConstraintStatements renders the statements that validates the constraints of the schema of _td_
+// on the go expression _expr_. See `ValueStatements` for _path_ and _depth_.
+func ConstraintStatements(
+	ctx *GeneratorContext,
+	file *GoFile,
+	expr, path string,
+	td *gentypes.TypeDefinition,
+	depth int) ([]string, error) {
+
+	if td.IsPrimitive() && ContainsString(textGoTypes, td.GoType.Name) {
+		// The string constraints applies to the text representation, e.g. of a `time.Time`
+		text := *td
+		text.GoType = &gentypes.GoType{Name: "string"}
+
+		return ConstraintStatements(ctx, file, file.Qualify(RuntimePackage, "Text")+"("+expr+")", path, &text, depth)
+	}
+
+	schema := td.Schema
+	statements := []string{}
+
+	check := func(condition, message string, args ...string) {
+		statements = append(statements, fmt.Sprintf(
+			"if %s {\nreturn %s\n}", condition, errorf(file, path, message, args...),
+		))
+	}
+
+	switch {
+	case td.IsPrimitive() && td.GoType.Name == "string":
+		if schema.MinLength > 0 {
+			file.Import("unicode/utf8")
+			check(
+				fmt.Sprintf("utf8.RuneCountInString(string(%s)) < %d", expr, schema.MinLength),
+				fmt.Sprintf("length must be at least %d", schema.MinLength),
+			)
+		}
+
+		if schema.MaxLength != nil {
+			file.Import("unicode/utf8")
+			check(
+				fmt.Sprintf("utf8.RuneCountInString(string(%s)) > %d", expr, *schema.MaxLength),
+				fmt.Sprintf("length must be at most %d", *schema.MaxLength),
+			)
+		}
+
+		for _, pattern := range SchemaPatterns(schema) {
+			name, err := patternVar(file, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", td.ID.String(), err.Error())
+			}
+
+			check(
+				fmt.Sprintf("!%s.MatchString(string(%s))", name, expr),
+				"must match pattern %s", strconv.Quote(pattern),
+			)
+		}
+
+	case td.IsPrimitive() && IsNumericGoType(td.GoType.Name):
+		if schema.Min != nil {
+			op, text := "<", "at least"
+			if schema.ExclusiveMin {
+				op, text = "<=", "greater than"
+			}
+
+			min := formatFloat(*schema.Min)
+			check(fmt.Sprintf("float64(%s) %s %s", expr, op, min), fmt.Sprintf("must be %s %s", text, min))
+		}
+
+		if schema.Max != nil {
+			op, text := ">", "at most"
+			if schema.ExclusiveMax {
+				op, text = ">=", "less than"
+			}
+
+			max := formatFloat(*schema.Max)
+			check(fmt.Sprintf("float64(%s) %s %s", expr, op, max), fmt.Sprintf("must be %s %s", text, max))
+		}
+
+		if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+			multiple := formatFloat(*schema.MultipleOf)
+
+			if IsIntegerGoType(td.GoType.Name) &&
+				*schema.MultipleOf == math.Trunc(*schema.MultipleOf) && *schema.MultipleOf <= math.MaxInt32 {
+				// Exact when both the value and multipleOf are integers
+				check(
+					fmt.Sprintf("%s%%%s != 0", expr, multiple),
+					fmt.Sprintf("must be a multiple of %s", multiple),
+				)
+			} else {
+				check(
+					fmt.Sprintf("!%s(float64(%s), %s)", file.Qualify(RuntimePackage, "IsMultipleOf"), expr, multiple),
+					fmt.Sprintf("must be a multiple of %s", multiple),
+				)
+			}
+		}
+
+	case td.IsArray():
+		if schema.MinItems > 0 {
+			check(
+				fmt.Sprintf("len(%s) < %d", expr, schema.MinItems),
+				fmt.Sprintf("must have at least %d items", schema.MinItems),
+			)
+		}
+
+		if schema.MaxItems != nil {
+			check(
+				fmt.Sprintf("len(%s) > %d", expr, *schema.MaxItems),
+				fmt.Sprintf("must have at most %d items", *schema.MaxItems),
+			)
+		}
+
+		items := ctx.ResolveDefinition(td.Items)
+		if items == nil {
+			return nil, fmt.Errorf("could not resolve array items: %s", td.ID.String())
+		}
+
+		index := fmt.Sprintf("i%d", depth)
+
+		if schema.UniqueItems && items.IsPrimitive() && IsBuiltinGoType(items.GoType.Name) &&
+			items.GoType.Name != "[]byte" {
+
+			itemType, err := GoTypeExprOf(ctx, file, items)
+			if err != nil {
+				return nil, err
+			}
+
+			seen := fmt.Sprintf("seen%d", depth)
+			statements = append(statements, fmt.Sprintf(
+				"%s := map[%s]bool{}\nfor %s := range %s {\nif %s[%s[%s]] {\nreturn %s\n}\n%s[%s[%s]] = true\n}",
+				seen, itemType, index, expr, seen, expr, index,
+				errorf(file, path, "items must be unique"),
+				seen, expr, index,
+			))
+		}
+
+		inner, err := ValueStatements(
+			ctx, file, fmt.Sprintf("%s[%s]", expr, index), elementPath(file, path, "[%d]", index), items, depth+1,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(inner) > 0 {
+			statements = append(statements, fmt.Sprintf(
+				"for %s := range %s {\n%s\n}", index, expr, joinStatements(inner),
+			))
+		}
+
+	case td.IsMap():
+		if schema.MinProps > 0 {
+			check(
+				fmt.Sprintf("len(%s) < %d", expr, schema.MinProps),
+				fmt.Sprintf("must have at least %d properties", schema.MinProps),
+			)
+		}
+
+		if schema.MaxProps != nil {
+			check(
+				fmt.Sprintf("len(%s) > %d", expr, *schema.MaxProps),
+				fmt.Sprintf("must have at most %d properties", *schema.MaxProps),
+			)
+		}
+
+		values := ctx.ResolveDefinition(td.AdditionalProperties)
+		if values == nil {
+			return nil, fmt.Errorf("could not resolve additional properties: %s", td.ID.String())
+		}
+
+		key := fmt.Sprintf("k%d", depth)
+
+		inner, err := ValueStatements(
+			ctx, file, fmt.Sprintf("%s[%s]", expr, key), elementPath(file, path, "[%q]", key), values, depth+1,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(inner) > 0 {
+			statements = append(statements, fmt.Sprintf(
+				"for %s := range %s {\n%s\n}", key, expr, joinStatements(inner),
+			))
+		}
+	}
+
+	not, err := NotStatements(ctx, file, expr, path, td, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(statements, not...), nil
+}
+
+// errorf renders a `fmt.Errorf` expression with the _message_ prefixed with _path_ (if any).
+// The _args_ are go expressions to the message format verbs.
+func errorf(file *GoFile, path, message string, args ...string) string {
+	format := strconv.Quote(message)
+	if path != "" {
+		format = strconv.Quote("%s: " + message)
+		args = append([]string{path}, args...)
+	}
+
+	if len(args) == 0 {
+		file.Import("errors")
+		return fmt.Sprintf("errors.New(%s)", format)
+	}
+
+	file.Import("fmt")
+
+	result := "fmt.Errorf(" + format
+	for _, arg := range args {
+		result += ", " + arg
+	}
+
+	return result + ")"
+}
+
+// elementPath renders the path expression of an element in an array or map.
+func elementPath(file *GoFile, path, verb, index string) string {
+	file.Import("fmt")
+
+	if path == "" {
+		return fmt.Sprintf("fmt.Sprintf(%s, %s)", strconv.Quote(verb), index)
+	}
+
+	return fmt.Sprintf("fmt.Sprintf(%s, %s, %s)", strconv.Quote("%s"+verb), path, index)
+}
+
+// patternVar registers a package level compiled regular expression in _file_ and
+// returns its name.
+func patternVar(file *GoFile, pattern string) (string, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("pattern '%s' is not supported: %s", pattern, err.Error())
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(pattern))
+
+	name := fmt.Sprintf("pattern%08x", h.Sum32())
+
+	file.Import("regexp")
+	file.AddVar(fmt.Sprintf("var %s = regexp.MustCompile(%s)", name, strconv.Quote(pattern)))
+
+	return name, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func joinStatements(statements []string) string {
+	result := ""
+	for i, statement := range statements {
+		if i > 0 {
+			result += "\n"
+		}
+
+		result += statement
+	}
+
+	return result
+}
+
+// blockStatements renders the _statements_ within the braces of a block.
+func blockStatements(statements []string) string {
+	if len(statements) == 0 {
+		return "\n"
+	}
+
+	return "\n" + joinStatements(statements) + "\n"
+}
@@ -0,0 +1,406 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+const (
+	// VariantRequest is the suffix of the request variant of a type. It has no _readOnly_
+	// properties.
+	VariantRequest = "Request"
+	// VariantResponse is the suffix of the response variant of a type. It has no _writeOnly_
+	// properties.
+	VariantResponse = "Response"
+)
+
+// HasVariants returns `true` when _td_ is rendered with request and response variants. That is
+// when it is a struct with a _readOnly_ or _writeOnly_ property, or a property of a struct type
+// that has variants. A property, or additional properties, of an inline array or map is checked
+// by its items or values.
+func HasVariants(ctx *GeneratorContext, td *gentypes.TypeDefinition) bool {
+	return hasVariants(ctx, td, map[string]bool{})
+}
+
+func hasVariants(ctx *GeneratorContext, td *gentypes.TypeDefinition, visited map[string]bool) bool {
+	if !td.IsObject() || !IsNamedType(td) {
+		return false
+	}
+
+	if result, ok := visited[td.ID.String()]; ok {
+		return result
+	}
+
+	// Break cycles, the result is settled by the properties that are not cyclic
+	visited[td.ID.String()] = false
+
+	for _, property := range AllProperties(ctx, td) {
+		property_td := ctx.ResolveDefinition(&property.ComponentDefinition)
+		if property_td == nil {
+			continue
+		}
+
+		if property_td.Schema.ReadOnly || property_td.Schema.WriteOnly || hasElementVariants(ctx, property_td, visited) {
+			visited[td.ID.String()] = true
+			return true
+		}
+	}
+
+	if td.AdditionalProperties != nil {
+		if values := ctx.ResolveDefinition(td.AdditionalProperties); values != nil && hasElementVariants(ctx, values, visited) {
+			visited[td.ID.String()] = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasElementVariants returns `true` when _td_ is a struct type that has variants or an inline
+// array or map where the items, or values, has variants.
+func hasElementVariants(ctx *GeneratorContext, td *gentypes.TypeDefinition, visited map[string]bool) bool {
+	switch {
+	case IsNamedType(td):
+		return hasVariants(ctx, td, visited)
+	case td.IsArray():
+		items := ctx.ResolveDefinition(td.Items)
+		return items != nil && hasElementVariants(ctx, items, visited)
+	case td.IsMap():
+		values := ctx.ResolveDefinition(td.AdditionalProperties)
+		return values != nil && hasElementVariants(ctx, values, visited)
+	}
+
+	return false
+}
+
+// AllProperties returns the properties of _td_ including those of its compositions. A property
+// declared by _td_ replaces a property with the same name in a composition.
+func AllProperties(ctx *GeneratorContext, td *gentypes.TypeDefinition) []*gentypes.Property {
+	properties := []*gentypes.Property{}
+
+	add := func(property *gentypes.Property) {
+		for i := range properties {
+			if properties[i].PropertyName == property.PropertyName {
+				properties[i] = property
+				return
+			}
+		}
+
+		properties = append(properties, property)
+	}
+
+	for i := range td.Composition {
+		if composed := ctx.ResolveDefinition(&td.Composition[i].ComponentDefinition); composed != nil {
+			for _, property := range AllProperties(ctx, composed) {
+				add(property)
+			}
+		}
+	}
+
+	for i := range td.Properties {
+		add(&td.Properties[i])
+	}
+
+	return properties
+}
+
+// DeclareVariants declares the request and response variants of _td_ when enabled in the
+// settings and _td_ has variants, see `HasVariants`. The conversion methods to the variants
+// are added to _decl_.
+//
+// The variants are flat structs, i.e. compositions are not embedded. A property of a struct
+// type that has variants is of the corresponding variant type. The same applies to the items
+// and values of inline arrays and maps, e.g. `[]AddressRequest`, and the additional properties.
+//
+// NOTE: Named array and map types are kept as is in the variants.
+func DeclareVariants(
+	ctx *GeneratorContext,
+	file *GoFile,
+	td *gentypes.TypeDefinition,
+	decl *GoTypeDecl) ([]*GoTypeDecl, error) {
+
+	if !ctx.settings.request_response_variants || !HasVariants(ctx, td) {
+		return nil, nil
+	}
+
+	variants := []*GoTypeDecl{}
+
+	for _, variant := range []string{VariantRequest, VariantResponse} {
+		vdecl, err := declareVariant(ctx, file, td, decl, variant)
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, vdecl)
+	}
+
+	return variants, nil
+}
+
+func declareVariant(
+	ctx *GeneratorContext,
+	file *GoFile,
+	td *gentypes.TypeDefinition,
+	decl *GoTypeDecl,
+	variant string) (*GoTypeDecl, error) {
+
+	excluded := "read only"
+	if variant == VariantResponse {
+		excluded = "write only"
+	}
+
+	vdecl := &GoTypeDecl{
+		Name:       decl.Name + variant,
+		Kind:       GoKindStruct,
+		Definition: td,
+		Doc: []string{fmt.Sprintf(
+			"%s%s is the %s variant of %s without the %s properties.",
+			decl.Name, variant, strings.ToLower(variant), decl.Name, excluded,
+		)},
+	}
+
+	to := []string{}
+	from := []string{}
+
+	for _, property := range AllProperties(ctx, td) {
+		property_td := ctx.ResolveDefinition(&property.ComponentDefinition)
+		if property_td == nil {
+			return nil, fmt.Errorf("could not resolve property: %s", property.ID.String())
+		}
+
+		if variant == VariantRequest && property_td.Schema.ReadOnly ||
+			variant == VariantResponse && property_td.Schema.WriteOnly {
+			continue
+		}
+
+		if err := declareField(ctx, file, property, vdecl); err != nil {
+			return nil, err
+		}
+
+		field := vdecl.Fields[len(vdecl.Fields)-1]
+
+		if !hasElementVariants(ctx, property_td, map[string]bool{}) {
+			to = append(to, fmt.Sprintf("t.%s = v.%s", field.Name, field.Name))
+			from = append(from, fmt.Sprintf("t.%s = v.%s", field.Name, field.Name))
+			continue
+		}
+
+		goType, err := variantTypeExpr(ctx, file, property_td, variant)
+		if err != nil {
+			return nil, err
+		}
+
+		field.Type = field.Presence.Wrap(file, goType)
+
+		statement, err := convertStatement(ctx, file, field, property_td, variant, goType)
+		if err != nil {
+			return nil, err
+		}
+
+		to = append(to, statement)
+
+		if goType, err = GoTypeExprOf(ctx, file, property_td); err != nil {
+			return nil, err
+		}
+
+		if statement, err = convertStatement(ctx, file, field, property_td, "", goType); err != nil {
+			return nil, err
+		}
+
+		from = append(from, statement)
+	}
+
+	if decl.AdditionalProperties != "" {
+		vdecl.AdditionalProperties = decl.AdditionalProperties
+		vdecl.KnownProperties = decl.KnownProperties
+
+		for _, field := range decl.Fields {
+			if field.Name == AdditionalPropertiesField && !field.Embedded && field.Property == nil {
+				vfield := *field
+				vdecl.Fields = append(vdecl.Fields, &vfield)
+			}
+		}
+
+		values := ctx.ResolveDefinition(td.AdditionalProperties)
+		if values == nil {
+			return nil, fmt.Errorf("could not resolve additional properties: %s", td.ID.String())
+		}
+
+		if !hasElementVariants(ctx, values, map[string]bool{}) {
+			to = append(to, fmt.Sprintf("t.%s = v.%s", AdditionalPropertiesField, AdditionalPropertiesField))
+			from = append(from, fmt.Sprintf("t.%s = v.%s", AdditionalPropertiesField, AdditionalPropertiesField))
+		} else {
+			goType, err := variantTypeExpr(ctx, file, values, variant)
+			if err != nil {
+				return nil, err
+			}
+
+			vdecl.AdditionalProperties = goType
+			vdecl.Fields[len(vdecl.Fields)-1].Type = "map[string]" + goType
+
+			dst, src := "t."+AdditionalPropertiesField, "v."+AdditionalPropertiesField
+
+			statement, err := convertCollection(ctx, file, dst, src, "map[string]"+goType, values, variant, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			to = append(to, statement)
+
+			if statement, err = convertCollection(
+				ctx, file, dst, src, "map[string]"+decl.AdditionalProperties, values, "", 0,
+			); err != nil {
+				return nil, err
+			}
+
+			from = append(from, statement)
+		}
+	}
+
+	var err error
+	if vdecl.Validate, err = ValidateStatements(ctx, file, vdecl); err != nil {
+		return nil, err
+	}
+
+	decl.Methods = append(decl.Methods, fmt.Sprintf(
+		"// To%s converts %s into a %s, the %s properties are dropped.\n"+
+			"func (v %s) To%s() %s {\nvar t %s\n%s\nreturn t\n}",
+		variant, decl.Name, vdecl.Name, excluded,
+		decl.Name, variant, vdecl.Name, vdecl.Name, joinStatements(to),
+	))
+
+	vdecl.Methods = append(vdecl.Methods, fmt.Sprintf(
+		"// To%s converts %s into a %s.\n"+
+			"func (v %s) To%s() %s {\nvar t %s\n%s\nreturn t\n}",
+		decl.Name, vdecl.Name, decl.Name,
+		vdecl.Name, decl.Name, decl.Name, decl.Name, joinStatements(from),
+	))
+
+	return vdecl, nil
+}
+
+// variantTypeExpr returns the go type expression of the _variant_ of _td_, which is a struct
+// type or an inline array or map, see `hasElementVariants`.
+func variantTypeExpr(ctx *GeneratorContext, file *GoFile, td *gentypes.TypeDefinition, variant string) (string, error) {
+	if !hasElementVariants(ctx, td, map[string]bool{}) {
+		return GoTypeExprOf(ctx, file, td)
+	}
+
+	switch {
+	case IsNamedType(td):
+		goType, err := GoTypeExprOf(ctx, file, td)
+		return goType + variant, err
+	case td.IsArray():
+		items, err := variantTypeExpr(ctx, file, ctx.ResolveDefinition(td.Items), variant)
+		return "[]" + items, err
+	}
+
+	values, err := variantTypeExpr(ctx, file, ctx.ResolveDefinition(td.AdditionalProperties), variant)
+	return "map[string]" + values, err
+}
+
+// convertStatement renders the statement that converts the field, of _td_, into the _variant_
+// or, when _variant_ is empty, from the variant. The _goType_ is the type of the converted field
+// without its presence.
+func convertStatement(
+	ctx *GeneratorContext,
+	file *GoFile,
+	field *GoField,
+	td *gentypes.TypeDefinition,
+	variant string,
+	goType string) (string, error) {
+
+	convert := func(dst, src string) (string, error) {
+		return convertValue(ctx, file, dst, src, goType, td, variant, 0)
+	}
+
+	switch field.Presence {
+	case PresencePointer:
+		statement, err := convert("converted", "(*v."+field.Name+")")
+		return fmt.Sprintf(
+			"if v.%s != nil {\nvar converted %s\n%s\nt.%s = &converted\n}",
+			field.Name, goType, statement, field.Name,
+		), err
+	case PresenceOptional:
+		statement, err := convert("t."+field.Name+".Value", "v."+field.Name+".Value")
+		return fmt.Sprintf(
+			"%s\nt.%s.Present = v.%s.Present", statement, field.Name, field.Name,
+		), err
+	case PresenceNullable:
+		statement, err := convert("t."+field.Name+".Value", "v."+field.Name+".Value")
+		return fmt.Sprintf(
+			"%s\nt.%s.Present, t.%s.Null = v.%s.Present, v.%s.Null",
+			statement, field.Name, field.Name, field.Name, field.Name,
+		), err
+	}
+
+	return convert("t."+field.Name, "v."+field.Name)
+}
+
+// convertValue renders the statement that converts _src_ of _td_ into _dst_ of _goType_. A
+// struct type is converted by its conversion method into the _variant_ or, when _variant_ is
+// empty, back into the type. An inline array or map is converted item by item.
+func convertValue(
+	ctx *GeneratorContext,
+	file *GoFile,
+	dst, src, goType string,
+	td *gentypes.TypeDefinition,
+	variant string,
+	depth int) (string, error) {
+
+	if !hasElementVariants(ctx, td, map[string]bool{}) {
+		return fmt.Sprintf("%s = %s", dst, src), nil
+	}
+
+	if IsNamedType(td) {
+		method := "To" + variant
+		if variant == "" {
+			method = "To" + GoTypeName(td)
+		}
+
+		return fmt.Sprintf("%s = %s.%s()", dst, src, method), nil
+	}
+
+	component := td.Items
+	if td.IsMap() {
+		component = td.AdditionalProperties
+	}
+
+	element := ctx.ResolveDefinition(component)
+	if element == nil {
+		return "", fmt.Errorf("could not resolve component: %s", component.ID.String())
+	}
+
+	return convertCollection(ctx, file, dst, src, goType, element, variant, depth)
+}
+
+// convertCollection renders the statement that converts the array or map _src_ into _dst_ of
+// _goType_ by converting each item, or value, of _element_, see `convertValue`.
+func convertCollection(
+	ctx *GeneratorContext,
+	file *GoFile,
+	dst, src, goType string,
+	element *gentypes.TypeDefinition,
+	variant string,
+	depth int) (string, error) {
+
+	key := fmt.Sprintf("i%d", depth)
+	elementType := strings.TrimPrefix(goType, "[]")
+
+	if strings.HasPrefix(goType, "map[string]") {
+		key = fmt.Sprintf("k%d", depth)
+		elementType = strings.TrimPrefix(goType, "map[string]")
+	}
+
+	statement, err := convertValue(ctx, file, dst+"["+key+"]", src+"["+key+"]", elementType, element, variant, depth+1)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"if %s != nil {\n%s = make(%s, len(%s))\nfor %s := range %s {\n%s\n}\n}",
+		src, dst, goType, src, key, src, statement,
+	), nil
+}
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -21,36 +22,68 @@ func IsReference(ref *openapi3.SchemaRef) bool {
 // ResolveReferenceAndSwitchIfNeeded will create a `ComponentReference`. If the _ref_ is under the specification root path
 // then it will be used. If it is under model root path it will use that instead to create the `ComponentReference`
 // as root path. If specification and module is on the same root path, the the longest path will be used as root path.
+//
+// The _ref_ is resolved relative to the module of _componentId_, i.e. a local reference such as `#/MyType` will
+// reside in the same module and a relative reference such as `../other.yaml#/MyType` is relative to the directory
+// of the _componentId_ module.
+//
+// If the _ref_ is neither under the specification nor the model root path, an error is returned.
 func ResolveReferenceAndSwitchIfNeeded(
 	ctx *GeneratorContext,
 	componentId *gentypes.ComponentReference,
 	ref *openapi3.SchemaRef,
-) *gentypes.ComponentReference {
+) (*gentypes.ComponentReference, error) {
+	idx := strings.Index(ref.Ref, "#/")
+	if idx == -1 {
+		// Not a json pointer, let the component reference report it
+		return gentypes.FromSchemaRef(ref, componentId.RootPath), nil
+	}
+
+	file, typeName := ref.Ref[:idx], ref.Ref[idx+2:]
+
+	if file == "" {
+		// Local reference -> same module as component id
+		return gentypes.NewComponentReference(
+			typeName, componentId.Module, componentId.Path, componentId.RootPath,
+		), nil
+	}
+
 	// Create the fully qualified path to the reference
-	ref_path := filepath.Join(componentId.RootPath, componentId.Path, ref.Ref)
+	ref_path := filepath.Join(componentId.RootPath, componentId.Path, file)
 	ref_path = filepath.Clean(ref_path)
 
-	var type_ref *gentypes.ComponentReference
-
+	root_path := ctx.settings.model_root
 	if IsSpecificationRooted(ctx, ref_path) {
-		if Is_A_SpecificationRef(ctx, componentId) {
-			// Specification rooted component id -> no switch
-			type_ref = gentypes.FromSchemaRef(ref, ctx.settings.spec_root)
-		} else {
-			// Switch root to module root
-			type_ref = gentypes.FromSchemaRef(ref, ctx.settings.model_root)
-		}
-	} else {
-		if !Is_A_SpecificationRef(ctx, componentId) {
-			// Module rooted component id -> no switch
-			type_ref = gentypes.FromSchemaRef(ref, ctx.settings.model_root)
-		} else {
-			// Switch root to spec root
-			type_ref = gentypes.FromSchemaRef(ref, ctx.settings.spec_root)
-		}
+		root_path = ctx.settings.spec_root
+	}
+
+	if !strings.HasPrefix(ref_path, root_path) {
+		return nil, fmt.Errorf(
+			"reference %s in %s: path '%s' is above root path '%s'", ref.Ref, componentId.String(), ref_path, root_path,
+		)
+	}
+
+	return gentypes.NewComponentReference(
+		typeName,
+		filepath.Base(ref_path),
+		gentypes.TrimPath(strings.TrimPrefix(filepath.Dir(ref_path), root_path)),
+		root_path,
+	), nil
+}
+
+// CreateReferencedComponent makes sure that the component that _ref_ points to is created. The _refId_ is
+// the already resolved reference, see `ResolveReferenceAndSwitchIfNeeded`.
+func CreateReferencedComponent(
+	ctx *GeneratorContext,
+	refId *gentypes.ComponentReference,
+	ref *openapi3.SchemaRef,
+) error {
+	if ctx.resolver.ResolveComponent(refId) != nil {
+		return nil
 	}
 
-	return type_ref
+	_, err := CreateComponentFromDefinition(ctx, refId, ref.Value)
+	return err
 }
 
 func ResolveGoPackage(ctx *GeneratorContext, ref *gentypes.ComponentReference) string {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
 )
 
 type Include struct {
@@ -22,11 +23,42 @@ type Settings struct {
 	spec          string
 	inclusion     []Include
 	templates     Templates
+	type_mapping  TypeMapping
+	// request_response_variants is set when _Request_ and _Response_ variants are rendered
+	// for types with _readOnly_ or _writeOnly_ properties.
+	request_response_variants bool
+	// defaults_on_unmarshal is set when structs applies the defaults when unmarshalled.
+	defaults_on_unmarshal bool
+	// optional_policy is how optional and nullable properties are represented.
+	optional_policy OptionalPolicy
+	// patch_types is set when a JSON Merge Patch type is rendered for each struct.
+	patch_types bool
+	// tag_rules are the struct tags rendered in addition to the json tag.
+	tag_rules []TagRule
+	// xml is set when xml tags are rendered from the schema _xml_ objects.
+	xml bool
+	// examples is set when godoc examples are rendered from the schema examples.
+	examples bool
+	// round_trips is set when round-trip and fuzz tests are rendered for the named types.
+	round_trips bool
+	// round_trip_fakes is the number of fake values of each type in the round-trip tests.
+	round_trip_fakes int
+	// faker creates the faker of the fake values in the round-trip tests.
+	faker func(ctx *GeneratorContext) ValueFaker
+	// plugins are the external generators that are run after the go files are rendered.
+	plugins []Plugin
+	// visitors are invoked while the components are processed and rendered.
+	visitors []Visitor
+	// resolver is the resolver of the components, a new `gentypes.ReferenceResolverImpl` is
+	// used for each generation when `nil`.
+	resolver gentypes.ReferenceResolver
 }
 
 func NewSettings(templates Templates) *Settings {
 	return &Settings{
-		templates: templates,
+		templates:       templates,
+		type_mapping:    DefaultTypeMapping(),
+		optional_policy: OptionalPointer,
 	}
 }
 
@@ -115,3 +147,137 @@ func (sett *Settings) UseLoader(loader *openapi3.Loader) *Settings {
 	sett.loader = loader
 	return sett
 }
+
+// UseTypeMapping registers a go type for the _OpenAPI_ _typ_ and _format_ pair. When
+// _format_ is empty it is the default go type for _typ_.
+//
+// For example "number", "decimal", `gentypes.GoType{Name: "decimal.Decimal", Import: "github.com/shopspring/decimal"}`.
+//
+// NOTE: A single schema or property may override this using the `x-go-type` and
+// `x-go-type-import` extensions.
+func (sett *Settings) UseTypeMapping(typ, format string, goType gentypes.GoType) *Settings {
+	if sett.type_mapping == nil {
+		sett.type_mapping = DefaultTypeMapping()
+	}
+
+	sett.type_mapping[TypeMappingKey{Type: typ, Format: format}] = goType
+	return sett
+}
+
+// UseRequestResponseVariants enables rendering of `<Type>Request` and `<Type>Response`
+// variants for each struct that has _readOnly_ or _writeOnly_ properties, directly or in
+// any of its property types. The request variant has no _readOnly_ properties and the
+// response variant no _writeOnly_ properties.
+//
+// Conversions are rendered as `<Type>.ToRequest()`, `<Type>.ToResponse()` and
+// `<Type>Request.To<Type>()`, `<Type>Response.To<Type>()`.
+func (sett *Settings) UseRequestResponseVariants(enabled bool) *Settings {
+	sett.request_response_variants = enabled
+	return sett
+}
+
+// UseDefaultsOnUnmarshal will make the generated structs apply the defaults, see the
+// rendered `ApplyDefaults` method, when unmarshalled from json.
+func (sett *Settings) UseDefaultsOnUnmarshal(enabled bool) *Settings {
+	sett.defaults_on_unmarshal = enabled
+	return sett
+}
+
+// UseOptionalPolicy sets how optional and nullable properties are rendered, see
+// `OptionalPolicy`. The default is `OptionalPointer`.
+//
+// Use `OptionalGeneric` when absent, `null` and a value needs to be distinguished, e.g. for
+// _PATCH_ requests. A single property may override the policy using the `x-go-optional`
+// extension, e.g. `x-go-optional: generic`.
+//
+// NOTE: With `OptionalPointer` and `OptionalValue`, a required and nullable property can
+// not be validated as required since absent and `null` are both `nil`.
+func (sett *Settings) UseOptionalPolicy(policy OptionalPolicy) *Settings {
+	sett.optional_policy = policy
+	return sett
+}
+
+// UsePatchTypes enables rendering of a `<Type>Patch` JSON Merge Patch (RFC 7396) for each
+// struct, where all properties are a `types.Nullable`. The patch is applied using the
+// `<Type>.Apply(patch)` method and created from two values using `Diff<Type>(from, to)`.
+func (sett *Settings) UsePatchTypes(enabled bool) *Settings {
+	sett.patch_types = enabled
+	return sett
+}
+
+// UseTagRules adds struct tags, in addition to the `json` tag, that are rendered on each
+// property field, e.g. `yaml`, `bson` or `db`. See `TagRule` for the name casing, omitempty
+// policy and custom tag values, and `ValidateTagRule` for `validate` tags.
+//
+// A single property may add or override tags using the `x-go-tags` extension, e.g.
+// `x-go-tags: {bson: "_id"}`.
+func (sett *Settings) UseTagRules(rules ...TagRule) *Settings {
+	sett.tag_rules = append(sett.tag_rules, rules...)
+	return sett
+}
+
+// UseXML enables rendering of `xml` struct tags, and `XMLName` fields, from the _xml_ objects
+// of the schemas such that the structs can be marshalled using `encoding/xml`. See `XMLTag`.
+//
+// NOTE: Maps and additional properties are not marshalled as xml.
+func (sett *Settings) UseXML(enabled bool) *Settings {
+	sett.xml = enabled
+	return sett
+}
+
+// UseExamples enables rendering of an _example_test.go_ in each package with an
+// `Example<Type>` function for each example of the named types, see `SchemaExamples`. The
+// function unmarshals the example and prints it as json such that `go test` verifies that
+// the generated types still matches the examples.
+//
+// NOTE: The output is not verified when the defaults are applied on unmarshal, see
+// `UseDefaultsOnUnmarshal`, since the printed json then includes the defaults.
+func (sett *Settings) UseExamples(enabled bool) *Settings {
+	sett.examples = enabled
+	return sett
+}
+
+// UseRoundTripTests enables rendering of a _roundtrip_test.go_ in each package with a
+// `TestRoundTrip<Type>` and a `FuzzUnmarshal<Type>` function for each named type, see
+// `DeclareRoundTrips`. The tests are seeded with the examples of the schemas and, when set
+// using `UseFakeValues`, fake values.
+func (sett *Settings) UseRoundTripTests(enabled bool) *Settings {
+	sett.round_trips = enabled
+	return sett
+}
+
+// UseFakeValues adds _count_ fake values of each type, produced by the faker that _faker_
+// creates, to the round-trip tests. The _faker_ is invoked once for each generation with the
+// processed specification, e.g. `fake.Factory(seed)`.
+func (sett *Settings) UseFakeValues(count int, faker func(ctx *GeneratorContext) ValueFaker) *Settings {
+	sett.round_trip_fakes = count
+	sett.faker = faker
+	return sett
+}
+
+// UsePlugins adds external generators that are handed the resolved model and returns files
+// to write to the output path, see `Plugin` and `RunPlugins`.
+//
+// NOTE: The plugins are only run when an output path is set, see `UseOutputPath`.
+func (sett *Settings) UsePlugins(plugins ...Plugin) *Settings {
+	sett.plugins = append(sett.plugins, plugins...)
+	return sett
+}
+
+// UseVisitors adds visitors that are invoked while the components are processed and rendered
+// in order to customize the generated code, see `Visitor`.
+func (sett *Settings) UseVisitors(visitors ...Visitor) *Settings {
+	sett.visitors = append(sett.visitors, visitors...)
+	return sett
+}
+
+// UseReferenceResolver sets the _resolver_ that keeps track of the components, e.g. backed by
+// a persistent cache or instrumented for debugging, see `gentypes.ReferenceResolver`.
+//
+// NOTE: The _resolver_ is not reset between generations, hence, it may be shared by generators
+// of multiple specifications such that these may resolve each other's components. A generation
+// only renders the types within its own go packages, see `NamedTypeDefinitions`.
+func (sett *Settings) UseReferenceResolver(resolver gentypes.ReferenceResolver) *Settings {
+	sett.resolver = resolver
+	return sett
+}
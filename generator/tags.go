@@ -0,0 +1,345 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/iancoleman/strcase"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// ExtensionGoTags adds or overrides struct tags of a property, e.g.
+// `x-go-tags: {bson: "_id", db: "-"}`. It is set on the inline property schema.
+const ExtensionGoTags = "x-go-tags"
+
+// TagCasing is how the property name is cased in a struct tag.
+type TagCasing string
+
+const (
+	// TagCasingAsIs keeps the property name as is.
+	TagCasingAsIs TagCasing = ""
+	// TagCasingSnake renders the property name in snake case e.g. "user_name".
+	TagCasingSnake TagCasing = "snake"
+	// TagCasingKebab renders the property name in kebab case e.g. "user-name".
+	TagCasingKebab TagCasing = "kebab"
+	// TagCasingCamel renders the property name in lower camel case e.g. "userName".
+	TagCasingCamel TagCasing = "camel"
+	// TagCasingPascal renders the property name in upper camel case e.g. "UserName".
+	TagCasingPascal TagCasing = "pascal"
+)
+
+// Apply renders _name_ in the casing.
+func (c TagCasing) Apply(name string) string {
+	switch c {
+	case TagCasingSnake:
+		return strcase.ToSnake(name)
+	case TagCasingKebab:
+		return strcase.ToKebab(name)
+	case TagCasingCamel:
+		return strcase.ToLowerCamel(name)
+	case TagCasingPascal:
+		return strcase.ToCamel(name)
+	}
+
+	return name
+}
+
+// OmitEmptyPolicy is when `,omitempty` is added to a struct tag.
+type OmitEmptyPolicy string
+
+const (
+	// OmitEmptyOptional adds `,omitempty` to properties that are not required, the same as
+	// for the `json` tag.
+	OmitEmptyOptional OmitEmptyPolicy = ""
+	// OmitEmptyAlways adds `,omitempty` to all properties.
+	OmitEmptyAlways OmitEmptyPolicy = "always"
+	// OmitEmptyNever never adds `,omitempty`.
+	OmitEmptyNever OmitEmptyPolicy = "never"
+)
+
+// TagRule renders a struct tag, in addition to the `json` tag, on each property field.
+//
+// By default the tag is the cased property name followed by `,omitempty` according to the
+// _OmitEmpty_ policy, e.g. `bson:"user_name,omitempty"`. A _Template_ or a _Value_ function
+// renders a custom tag value instead.
+type TagRule struct {
+	// Key is the tag key e.g. "yaml".
+	Key string
+	// Casing is the casing of the property name.
+	Casing TagCasing
+	// OmitEmpty is when `,omitempty` is added.
+	OmitEmpty OmitEmptyPolicy
+	// Template is an optional `text/template` that renders the tag value from a `TagData`,
+	// e.g. `{{.Name}}{{if .OmitEmpty}},omitempty{{end}}`.
+	Template string
+	// Value is an optional function that renders the tag value. It takes precedence over
+	// the _Template_. When it returns an empty string, the tag is not rendered.
+	Value func(data TagData) string
+}
+
+// TagData is the property information that a `TagRule` renders the tag value from.
+type TagData struct {
+	// Key is the tag key.
+	Key string
+	// Name is the property name in the casing of the rule.
+	Name string
+	// PropertyName is the property name as in the schema.
+	PropertyName string
+	// FieldName is the go field name.
+	FieldName string
+	// Required is set when the property is required.
+	Required bool
+	// Nullable is set when the property is nullable.
+	Nullable bool
+	// OmitEmpty is set when `,omitempty` should be added according to the rule.
+	OmitEmpty bool
+	// Schema is the schema of the property type.
+	Schema *openapi3.Schema
+}
+
+// FieldTag renders the complete struct tag of the _field_ rendered from _property_ of type
+// _td_. The `json` tag is rendered first, then the `xml` tag when enabled, see `XMLTag`, the
+// `TagRule`s in the settings and last the tags from the `x-go-tags` extension that are not
+// rendered by a rule, sorted by key.
+//
+// The `json` tag can not be customized since the generated marshalling depends on it.
+func FieldTag(
+	ctx *GeneratorContext,
+	property *gentypes.Property,
+	td *gentypes.TypeDefinition,
+	field *GoField) (string, error) {
+
+	overrides, err := tagOverrides(property)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := overrides["json"]; ok {
+		return "", fmt.Errorf("%s can not override the json tag (%s)", ExtensionGoTags, property.ID.String())
+	}
+
+	name := property.PropertyName
+	if !property.Required {
+		name += ",omitempty"
+	}
+
+	tags := []string{fmt.Sprintf(`json:"%s"`, name)}
+	rendered := map[string]bool{"json": true}
+
+	if ctx.settings.xml {
+		rendered["xml"] = true
+
+		value, ok := overrides["xml"]
+		if !ok {
+			value = XMLTag(property, td)
+		}
+
+		tags = append(tags, "xml:"+strconv.Quote(value))
+	}
+
+	for _, rule := range ctx.settings.tag_rules {
+		if rule.Key == "json" {
+			return "", fmt.Errorf("tag rule can not render the json tag")
+		}
+
+		if rendered[rule.Key] {
+			return "", fmt.Errorf("tag: %s is rendered more than once (%s)", rule.Key, property.ID.String())
+		}
+
+		rendered[rule.Key] = true
+
+		value, ok := overrides[rule.Key]
+		if !ok {
+			if value, err = rule.render(property, td, field); err != nil {
+				return "", fmt.Errorf("tag: %s %s: %s", rule.Key, property.ID.String(), err.Error())
+			}
+		}
+
+		if value != "" {
+			tags = append(tags, rule.Key+":"+strconv.Quote(value))
+		}
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		if !rendered[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		tags = append(tags, key+":"+strconv.Quote(overrides[key]))
+	}
+
+	return strings.Join(tags, " "), nil
+}
+
+// render renders the tag value of the rule.
+func (rule *TagRule) render(property *gentypes.Property, td *gentypes.TypeDefinition, field *GoField) (string, error) {
+	data := TagData{
+		Key:          rule.Key,
+		Name:         rule.Casing.Apply(property.PropertyName),
+		PropertyName: property.PropertyName,
+		FieldName:    field.Name,
+		Required:     property.Required,
+		Nullable:     td.Schema != nil && td.Schema.Nullable,
+		Schema:       td.Schema,
+	}
+
+	switch rule.OmitEmpty {
+	case OmitEmptyAlways:
+		data.OmitEmpty = true
+	case OmitEmptyOptional:
+		data.OmitEmpty = !property.Required
+	}
+
+	switch {
+	case rule.Value != nil:
+		return rule.Value(data), nil
+	case rule.Template != "":
+		tpl, err := template.New(rule.Key).Parse(rule.Template)
+		if err != nil {
+			return "", err
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return "", err
+		}
+
+		return buf.String(), nil
+	}
+
+	if data.OmitEmpty {
+		return data.Name + ",omitempty", nil
+	}
+
+	return data.Name, nil
+}
+
+// tagOverrides returns the tags of the `x-go-tags` extension on the inline _property_.
+func tagOverrides(property *gentypes.Property) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	if property.Definition == nil || property.Reference != nil {
+		return overrides, nil
+	}
+
+	switch v := property.Definition.Schema.Extensions[ExtensionGoTags].(type) {
+	case nil:
+	case json.RawMessage:
+		if err := json.Unmarshal(v, &overrides); err != nil {
+			return nil, fmt.Errorf(
+				"%s must be a map of tag keys to values (%s): %s", ExtensionGoTags, property.ID.String(), err.Error(),
+			)
+		}
+	case map[string]any:
+		for key, value := range v {
+			overrides[key] = fmt.Sprintf("%v", value)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be a map of tag keys to values (%s)", ExtensionGoTags, property.ID.String())
+	}
+
+	return overrides, nil
+}
+
+// ValidateTagRule returns a `TagRule` that renders `validate` tags, as used by
+// _github.com/go-playground/validator_, from the constraints of the schema.
+//
+// NOTE: Patterns are not rendered since they are not supported by the validator.
+func ValidateTagRule() TagRule {
+	return TagRule{Key: "validate", Value: validateTag}
+}
+
+func validateTag(data TagData) string {
+	schema := data.Schema
+
+	rules := []string{"omitempty"}
+	if data.Required && !data.Nullable {
+		rules = []string{"required"}
+	}
+
+	if schema == nil {
+		return rules[0]
+	}
+
+	bound := func(op string, value float64) {
+		rules = append(rules, fmt.Sprintf("%s=%s", op, formatFloat(value)))
+	}
+
+	switch schema.Type {
+	case "string":
+		if schema.MinLength > 0 {
+			rules = append(rules, fmt.Sprintf("min=%d", schema.MinLength))
+		}
+
+		if schema.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *schema.MaxLength))
+		}
+
+		switch schema.Format {
+		case "email", "uuid", "uri", "hostname", "ipv4", "ipv6":
+			rules = append(rules, schema.Format)
+		}
+	case "integer", "number":
+		if schema.Min != nil {
+			if schema.ExclusiveMin {
+				bound("gt", *schema.Min)
+			} else {
+				bound("gte", *schema.Min)
+			}
+		}
+
+		if schema.Max != nil {
+			if schema.ExclusiveMax {
+				bound("lt", *schema.Max)
+			} else {
+				bound("lte", *schema.Max)
+			}
+		}
+	case "array":
+		if schema.MinItems > 0 {
+			rules = append(rules, fmt.Sprintf("min=%d", schema.MinItems))
+		}
+
+		if schema.MaxItems != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *schema.MaxItems))
+		}
+
+		if schema.UniqueItems {
+			rules = append(rules, "unique")
+		}
+	}
+
+	if values := validateOneOf(schema.Enum); values != "" {
+		rules = append(rules, "oneof="+values)
+	}
+
+	return strings.Join(rules, ",")
+}
+
+// validateOneOf renders the _enum_ as the space separated values of a `oneof` rule, or an
+// empty string when a value can not be represented.
+func validateOneOf(enum []any) string {
+	values := make([]string, 0, len(enum))
+
+	for _, value := range enum {
+		s := fmt.Sprintf("%v", value)
+		if value == nil || s == "" || strings.ContainsAny(s, " ,|\"'`") {
+			return ""
+		}
+
+		values = append(values, s)
+	}
+
+	return strings.Join(values, " ")
+}
@@ -2,9 +2,9 @@ package generator
 
 import (
 	"embed"
-	"html/template"
 	"io/fs"
 	"strings"
+	"text/template"
 )
 
 type WellKnownTemplates string
@@ -16,8 +16,14 @@ const (
 	// This is when a spec do not exist and models are generated,
 	// otherwise a user specified spec is needed.
 	TemplateIndex WellKnownTemplates = "index.yaml"
+	// TemplateGoFile is the template that renders a go source file. It
+	// is defined in one of the _go/*.tmpl_ templates.
+	TemplateGoFile WellKnownTemplates = "file"
 )
 
+// goTemplates is the glob for all templates used when rendering go code.
+const goTemplates = "templates/go/*.tmpl"
+
 //go:embed templates
 var templates embed.FS
 
@@ -39,18 +45,52 @@ func (tpl *Templates) GetTemplate(fqName string) (*template.Template, error) {
 		fqName = "templates/" + fqName
 	}
 
-	t, err := template.ParseFS(tpl.templates, fqName)
-	if err == nil {
-		return t, nil
+	if tpl.templates != nil {
+		t, err := template.ParseFS(tpl.templates, fqName)
+		if err == nil {
+			return t, nil
+		}
 	}
 
 	return template.ParseFS(templates, fqName)
 }
 
+// GetGoTemplates returns all templates used to render go code.
+//
+// The embedded _templates/go/*.tmpl_ are parsed first and then any user provided
+// templates matching the same glob. Hence a user may override a single template
+// definition, e.g. `{{define "struct"}}`, without providing all templates.
+func (tpl *Templates) GetGoTemplates(funcs template.FuncMap) (*template.Template, error) {
+	t, err := template.New("go").Funcs(funcs).ParseFS(templates, goTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	if tpl.templates != nil {
+		if matches, err := fs.Glob(tpl.templates, goTemplates); err == nil && len(matches) > 0 {
+			if t, err = t.ParseFS(tpl.templates, goTemplates); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return t, nil
+}
+
 // GetFile will check if it exists in user provided templates folder
 // or in the embedded templates folder.
 func (tpl *Templates) GetFileAsString(fqPath string) (string, error) {
-	data, err := templates.ReadFile("templates/index.yaml")
+	if !strings.HasPrefix(fqPath, "templates/") {
+		fqPath = "templates/" + fqPath
+	}
+
+	if tpl.templates != nil {
+		if data, err := fs.ReadFile(tpl.templates, fqPath); err == nil {
+			return string(data), nil
+		}
+	}
+
+	data, err := templates.ReadFile(fqPath)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+const (
+	// ExtensionGoType is the extension that overrides the go type of a
+	// single schema or property, e.g. `x-go-type: decimal.Decimal`.
+	ExtensionGoType = "x-go-type"
+	// ExtensionGoTypeImport is the go package to import when `x-go-type`
+	// is used, e.g. `x-go-type-import: github.com/shopspring/decimal`.
+	ExtensionGoTypeImport = "x-go-type-import"
+	// RuntimePackage is the package containing the types that generated
+	// code depends on, such as `types.Date`.
+	RuntimePackage = "github.com/mariotoffia/go-openapi/types"
+)
+
+// TypeMappingKey is the _OpenAPI_ type and format pair that is mapped to
+// a go type. When _Format_ is empty, it is the default for the _Type_.
+type TypeMappingKey struct {
+	Type   string
+	Format string
+}
+
+// TypeMapping maps _OpenAPI_ type and format pairs to go types.
+type TypeMapping map[TypeMappingKey]gentypes.GoType
+
+// DefaultTypeMapping returns the built in type mapping.
+//
+// NOTE: The _decimal_ format has no default mapping since it depends on
+// the decimal implementation a project uses, register one using
+// `Settings.UseTypeMapping`. Until then it falls back to `float64` and a
+// diagnostic is added, see `ResolveGoType`.
+func DefaultTypeMapping() TypeMapping {
+	return TypeMapping{
+		{Type: "string"}:                      {Name: "string"},
+		{Type: "string", Format: "date-time"}: {Name: "time.Time", Import: "time"},
+		{Type: "string", Format: "date"}:      {Name: "types.Date", Import: RuntimePackage},
+		{Type: "string", Format: "uuid"}:      {Name: "types.UUID", Import: RuntimePackage},
+		{Type: "string", Format: "byte"}:      {Name: "[]byte"},
+		{Type: "string", Format: "binary"}:    {Name: "io.Reader", Import: "io"},
+		{Type: "integer"}:                     {Name: "int"},
+		{Type: "integer", Format: "int32"}:    {Name: "int32"},
+		{Type: "integer", Format: "int64"}:    {Name: "int64"},
+		{Type: "number"}:                      {Name: "float64"},
+		{Type: "number", Format: "float"}:     {Name: "float32"},
+		{Type: "number", Format: "double"}:    {Name: "float64"},
+		{Type: "boolean"}:                     {Name: "bool"},
+	}
+}
+
+// Clone returns a shallow copy of the type mapping.
+func (tm TypeMapping) Clone() TypeMapping {
+	clone := make(TypeMapping, len(tm))
+	for k, v := range tm {
+		clone[k] = v
+	}
+
+	return clone
+}
+
+// Resolve looks up the _typ_ and _format_ pair. If the format is not
+// registered, it will fall back on the default for _typ_.
+func (tm TypeMapping) Resolve(typ, format string) (gentypes.GoType, bool) {
+	goType, ok, _ := tm.resolve(typ, format)
+	return goType, ok
+}
+
+// resolve is `Resolve` that also returns whether it fell back on the default
+// for _typ_.
+func (tm TypeMapping) resolve(typ, format string) (goType gentypes.GoType, ok bool, fallback bool) {
+	if goType, ok := tm[TypeMappingKey{Type: typ, Format: format}]; ok {
+		return goType, true, false
+	}
+
+	goType, ok = tm[TypeMappingKey{Type: typ}]
+	return goType, ok, format != ""
+}
+
+// lossyFormats are the formats that lose precision when they fall back on the
+// default go type of their _OpenAPI_ type.
+var lossyFormats = map[TypeMappingKey]bool{
+	{Type: "number", Format: "decimal"}: true,
+}
+
+// IsPrimitiveSchema returns `true` if the _schema_ is of type string, integer,
+// number or boolean.
+func IsPrimitiveSchema(schema *openapi3.Schema) bool {
+	switch schema.Type {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+
+	return false
+}
+
+// ResolveGoType will resolve the go type for the primitive _schema_ of the
+// component _id_.
+//
+// If the schema has the `x-go-type` extension it has precedence over the
+// type mapping in the settings. If not a primitive schema, `nil` is returned.
+//
+// NOTE: When a format, such as _decimal_, falls back on a go type that can not
+// represent it exactly, a diagnostic is added to the _id_ component.
+func ResolveGoType(
+	ctx *GeneratorContext,
+	id *gentypes.ComponentReference,
+	schema *openapi3.Schema) (*gentypes.GoType, error) {
+
+	if goType, ok := GetExtensionString(schema, ExtensionGoType); ok && goType != "" {
+		goImport, _ := GetExtensionString(schema, ExtensionGoTypeImport)
+		return &gentypes.GoType{Name: goType, Import: goImport}, nil
+	}
+
+	if !IsPrimitiveSchema(schema) {
+		return nil, nil
+	}
+
+	goType, ok, fallback := ctx.settings.type_mapping.resolve(schema.Type, schema.Format)
+	if !ok {
+		return nil, fmt.Errorf("no go type mapping for type: %s format: %s", schema.Type, schema.Format)
+	}
+
+	if fallback && lossyFormats[TypeMappingKey{Type: schema.Type, Format: schema.Format}] {
+		ctx.AddDiagnostic(
+			id,
+			"format: %s has no type mapping, %s is used and may lose precision, register one using Settings.UseTypeMapping",
+			schema.Format, goType.Name,
+		)
+	}
+
+	return &goType, nil
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// HandleUnion will create the `TypeDefinition.OneOf` and `TypeDefinition.AnyOf` components when
+// _def_ is a _oneOf_ or _anyOf_ without a discriminator (see `HandleDiscriminatorBasedPolymorphism`).
+//
+// Inline members get a synthesized name from the union name and their _title_ or _type_, e.g.
+// `Value_String`.
+//
+// NOTE: When _def_ has properties or _allOf_, the _oneOf_ and _anyOf_ are not handled.
+func HandleUnion(
+	ctx *GeneratorContext,
+	td *gentypes.TypeDefinition,
+	component *gentypes.ComponentDefinition,
+	def *openapi3.Schema) error {
+
+	if len(def.Properties) > 0 || len(def.AllOf) > 0 {
+		return nil
+	}
+
+	var err error
+
+	if def.Discriminator == nil || def.Discriminator.PropertyName == "" {
+		if td.OneOf, err = createUnionMembers(ctx, component, def.OneOf); err != nil {
+			return err
+		}
+	}
+
+	td.AnyOf, err = createUnionMembers(ctx, component, def.AnyOf)
+	return err
+}
+
+func createUnionMembers(
+	ctx *GeneratorContext,
+	component *gentypes.ComponentDefinition,
+	members openapi3.SchemaRefs) ([]gentypes.ComponentDefinition, error) {
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	result := []gentypes.ComponentDefinition{}
+	names := []string{}
+
+	for i := range members {
+		member := members[i]
+
+		if IsReference(member) {
+			ref, err := ResolveReferenceAndSwitchIfNeeded(ctx, &component.ID, member)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := CreateReferencedComponent(ctx, ref, member); err != nil {
+				return nil, err
+			}
+
+			result = append(result, gentypes.ComponentDefinition{ID: *ref, Reference: ref})
+			continue
+		}
+
+		name := UnionMemberName(member.Value, i)
+		if ContainsString(names, name) {
+			name = UnionMemberName(&openapi3.Schema{}, i)
+		}
+
+		names = append(names, name)
+
+		inline, err := CreateInlineComponent(ctx, component.ID.NewWithAppendTypeName(name), member)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *inline)
+	}
+
+	return result, nil
+}
+
+// UnionMemberName synthesizes a stable name of a inline union member at _index_. It is the
+// _title_ or the _type_ of the _schema_. If neither is present it is "Option" and the index
+// (starting with 1).
+func UnionMemberName(schema *openapi3.Schema, index int) string {
+	if schema.Title != "" {
+		return schema.Title
+	}
+
+	if schema.Type != "" && schema.Type != "object" {
+		return schema.Type
+	}
+
+	return "Option" + strconv.Itoa(index+1)
+}
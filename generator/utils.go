@@ -1,14 +1,18 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// ContainsInterface will check if a value is in a slice of values. The values are compared
+// using `reflect.DeepEqual` since e.g. _enum_ values may be objects or arrays.
 func ContainsInterface(slice []any, s any) bool {
 	for i := range slice {
-		if s == slice[i] {
+		if reflect.DeepEqual(s, slice[i]) {
 			return true
 		}
 	}
@@ -60,3 +64,62 @@ func RemoveSchemaRef(references openapi3.SchemaRefs, ref *openapi3.SchemaRef) op
 
 	return references
 }
+
+// GetExtensionString returns the string value of the extension _name_ on
+// the _schema_. If the extension is not present or is not a string, _false_
+// is returned.
+//
+// NOTE: The loader keeps extension values as raw JSON, hence both raw JSON
+// and plain strings are supported.
+func GetExtensionString(schema *openapi3.Schema, name string) (string, bool) {
+	if schema == nil || schema.Extensions == nil {
+		return "", false
+	}
+
+	value, ok := schema.Extensions[name]
+	if !ok {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.RawMessage:
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return "", false
+		}
+
+		return s, true
+	}
+
+	return "", false
+}
+
+// GetExtensionBool returns the boolean value of the extension _name_ on the _schema_.
+// If the extension is not present or is not a boolean, _false_ is returned.
+func GetExtensionBool(schema *openapi3.Schema, name string) (bool, bool) {
+	if schema == nil || schema.Extensions == nil {
+		return false, false
+	}
+
+	switch v := schema.Extensions[name].(type) {
+	case bool:
+		return v, true
+	case json.RawMessage:
+		var b bool
+		if err := json.Unmarshal(v, &b); err != nil {
+			return false, false
+		}
+
+		return b, true
+	}
+
+	return false, false
+}
+
+// IsComposedSchema returns `true` if the _schema_ is composed using _allOf_, _oneOf_
+// or _anyOf_.
+func IsComposedSchema(schema *openapi3.Schema) bool {
+	return len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0
+}
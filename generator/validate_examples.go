@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// CheckExamples validates the _example_, the _examples_ and the _default_ of all schemas
+// against the fully resolved schema, see `ResolvedSchema`. Each value that do not match
+// is added as a diagnostic with the location in the source file, see `Locate`.
+//
+// The properties, array items and additional properties declared inline are checked as
+// part of the component they are declared in. A property is checked against the merged
+// property schema of all _allOf_ members.
+//
+// NOTE: The defaults are removed while the specification is validated when loaded, see
+// `StripDefaults`, such that all defaults that do not match are reported here.
+func CheckExamples(ctx *GeneratorContext) error {
+	roots := []*gentypes.TypeDefinition{}
+	for _, component := range ctx.resolver.Components() {
+		if component.Definition != nil && !component.Definition.Inline {
+			roots = append(roots, component.Definition)
+		}
+	}
+
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].ID.String() < roots[j].ID.String()
+	})
+
+	seen := map[*gentypes.TypeDefinition]bool{}
+
+	for _, td := range roots {
+		if err := checkExamples(ctx, seen, &td.ID, "", td, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StripDefaults removes the _default_ of all schemas reachable from _schemas_ and returns
+// the function that restores them.
+//
+// The validation of the specification fails, without a location, on the first _default_
+// that does not match its schema. Hence, the defaults are stripped while validating and
+// checked by `CheckExamples` instead.
+func StripDefaults(schemas openapi3.Schemas) func() {
+	defaults := map[*openapi3.Schema]any{}
+
+	var strip func(ref *openapi3.SchemaRef)
+	strip = func(ref *openapi3.SchemaRef) {
+		if ref == nil || ref.Value == nil {
+			return
+		}
+
+		schema := ref.Value
+		if _, seen := defaults[schema]; seen {
+			return
+		}
+
+		defaults[schema] = schema.Default
+		schema.Default = nil
+
+		for _, property := range schema.Properties {
+			strip(property)
+		}
+
+		for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+			for _, member := range refs {
+				strip(member)
+			}
+		}
+
+		strip(schema.Items)
+		strip(schema.AdditionalProperties)
+		strip(schema.Not)
+	}
+
+	for _, ref := range schemas {
+		strip(ref)
+	}
+
+	return func() {
+		for schema, value := range defaults {
+			schema.Default = value
+		}
+	}
+}
+
+// checkExamples checks the values of _td_, declared at _pointer_ in the _root_ component,
+// against _schema_ or, when `nil`, the resolved schema of _td_.
+func checkExamples(
+	ctx *GeneratorContext,
+	seen map[*gentypes.TypeDefinition]bool,
+	root *gentypes.ComponentReference,
+	pointer string,
+	td *gentypes.TypeDefinition,
+	schema *openapi3.Schema) error {
+
+	if td == nil || td.Schema == nil || seen[td] {
+		return nil
+	}
+
+	seen[td] = true
+
+	if schema == nil {
+		schema = ResolvedSchema(ctx, td)
+	}
+
+	examples, err := DeclaredExamples(td)
+	if err != nil {
+		return err
+	}
+
+	if td.Schema.Default != nil {
+		examples = append(examples, SchemaExample{Value: td.Schema.Default, Pointer: "/default"})
+	}
+
+	for _, example := range examples {
+		if err := schema.VisitJSON(example.Value, openapi3.MultiErrors()); err != nil {
+			ctx.AddDiagnosticAt(
+				root, ctx.Locate(root, pointer+example.Pointer),
+				"%s does not match the schema: %s",
+				strings.TrimPrefix(pointer+example.Pointer, "/"), schemaErrorReason(err),
+			)
+		}
+	}
+
+	for i := range td.Properties {
+		property := &td.Properties[i]
+		if property.Reference != nil {
+			continue
+		}
+
+		var property_schema *openapi3.Schema
+		if ref, ok := schema.Properties[property.PropertyName]; ok && ref.Value != nil {
+			property_schema = ref.Value
+		}
+
+		property_pointer := pointer + "/properties/" + EscapePointerToken(property.PropertyName)
+		if err := checkExamples(ctx, seen, root, property_pointer, property.Definition, property_schema); err != nil {
+			return err
+		}
+	}
+
+	if td.Items != nil && td.Items.Reference == nil {
+		if err := checkExamples(ctx, seen, root, pointer+"/items", td.Items.Definition, nil); err != nil {
+			return err
+		}
+	}
+
+	if values := td.AdditionalProperties; values != nil && values.Reference == nil {
+		if err := checkExamples(ctx, seen, root, pointer+"/additionalProperties", values.Definition, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResolvedSchema returns the schema of _td_ merged with the schemas of all its _allOf_
+// compositions using `MergeSchemaObjects`.
+//
+// NOTE: When the compositions can not be merged, the schema of _td_ is returned as is,
+// where the _allOf_ references are still in place.
+func ResolvedSchema(ctx *GeneratorContext, td *gentypes.TypeDefinition) *openapi3.Schema {
+	if len(td.Composition) == 0 {
+		return td.Schema
+	}
+
+	schema := td.Schema
+
+	for i := range td.Composition {
+		composed := ctx.ResolveDefinition(&td.Composition[i].ComponentDefinition)
+		if composed == nil {
+			return td.Schema
+		}
+
+		merged, err := MergeSchemaObjects(ctx, schema, ResolvedSchema(ctx, composed))
+		if err != nil {
+			return td.Schema
+		}
+
+		schema = merged
+	}
+
+	resolved := *schema
+	resolved.AllOf = nil
+
+	return &resolved
+}
+
+// schemaErrorReason renders the reasons of a schema validation _err_ without the schema
+// and value dumps of `openapi3.SchemaError`.
+func schemaErrorReason(err error) string {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		reasons := make([]string, 0, len(multi))
+		for _, err := range multi {
+			reasons = append(reasons, schemaErrorReason(err))
+		}
+
+		return strings.Join(reasons, "; ")
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			return fmt.Sprintf("%s: %s", "/"+strings.Join(pointer, "/"), schemaErr.Reason)
+		}
+
+		return schemaErr.Reason
+	}
+
+	return err.Error()
+}
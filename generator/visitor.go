@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// Visitor is invoked while the component graph is processed and rendered such that the
+// generated code can be customized without modifying the generator. Embed `NopVisitor` to
+// only implement some of the callbacks. The visitors are registered using
+// `Settings.UseVisitors`.
+//
+// The component callbacks are invoked when all components are created, see `VisitComponents`,
+// and before cycles, compositions and examples are checked. Hence, these may alter the type
+// definitions, e.g. remove properties or set the `x-go-name` extension using `SetExtension`
+// for custom naming.
+//
+// The render callbacks are invoked when all types are declared, see `Render`.
+//
+// NOTE: A `GoTypeDecl` is complete when `BeforeRender` is invoked, i.e. the `Validate`
+// statements are already rendered. Rename types and fields using `x-go-name` in the component
+// callbacks instead of in the declaration.
+type Visitor interface {
+	// OnComponent is invoked for each type definition, named and inline, sorted on id.
+	OnComponent(ctx *GeneratorContext, td *gentypes.TypeDefinition) error
+	// OnProperty is invoked for each property of _td_. The property is removed when _keep_
+	// is `false`.
+	OnProperty(ctx *GeneratorContext, td *gentypes.TypeDefinition, property *gentypes.Property) (keep bool, err error)
+	// OnComposition is invoked for each _allOf_ composition of _td_.
+	OnComposition(ctx *GeneratorContext, td *gentypes.TypeDefinition, composition *gentypes.Composition) error
+	// OnDiscriminator is invoked for each discriminated component of _td_.
+	OnDiscriminator(ctx *GeneratorContext, td *gentypes.TypeDefinition, dc *gentypes.DiscriminatorComponent) error
+	// BeforeRender is invoked for each type declared in _file_, e.g. to alter the tags and
+	// documentation of the fields or add methods.
+	BeforeRender(ctx *GeneratorContext, file *GoFile, decl *GoTypeDecl) error
+	// AfterRender is invoked with the rendered _files_ and returns the files to write, e.g.
+	// with additional files.
+	AfterRender(ctx *GeneratorContext, files []GeneratedFile) ([]GeneratedFile, error)
+}
+
+// NopVisitor is a `Visitor` that does nothing. Embed it in a visitor to only implement some
+// of the callbacks.
+type NopVisitor struct{}
+
+func (NopVisitor) OnComponent(ctx *GeneratorContext, td *gentypes.TypeDefinition) error {
+	return nil
+}
+
+func (NopVisitor) OnProperty(ctx *GeneratorContext, td *gentypes.TypeDefinition, property *gentypes.Property) (bool, error) {
+	return true, nil
+}
+
+func (NopVisitor) OnComposition(ctx *GeneratorContext, td *gentypes.TypeDefinition, composition *gentypes.Composition) error {
+	return nil
+}
+
+func (NopVisitor) OnDiscriminator(ctx *GeneratorContext, td *gentypes.TypeDefinition, dc *gentypes.DiscriminatorComponent) error {
+	return nil
+}
+
+func (NopVisitor) BeforeRender(ctx *GeneratorContext, file *GoFile, decl *GoTypeDecl) error {
+	return nil
+}
+
+func (NopVisitor) AfterRender(ctx *GeneratorContext, files []GeneratedFile) ([]GeneratedFile, error) {
+	return files, nil
+}
+
+// VisitComponents invokes the component callbacks of the visitors, in the order registered,
+// for all type definitions sorted on id.
+func VisitComponents(ctx *GeneratorContext) error {
+	if len(ctx.settings.visitors) == 0 {
+		return nil
+	}
+
+	seen := map[*gentypes.TypeDefinition]bool{}
+	definitions := []*gentypes.TypeDefinition{}
+
+	for _, component := range ctx.resolver.Components() {
+		if td := component.Definition; td != nil && !seen[td] {
+			seen[td] = true
+			definitions = append(definitions, td)
+		}
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID.String() < definitions[j].ID.String()
+	})
+
+	for _, visitor := range ctx.settings.visitors {
+		for _, td := range definitions {
+			if err := visitComponent(ctx, visitor, td); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func visitComponent(ctx *GeneratorContext, visitor Visitor, td *gentypes.TypeDefinition) error {
+	if err := visitor.OnComponent(ctx, td); err != nil {
+		return err
+	}
+
+	properties := td.Properties[:0]
+	for i := range td.Properties {
+		keep, err := visitor.OnProperty(ctx, td, &td.Properties[i])
+		if err != nil {
+			return err
+		}
+
+		if keep {
+			properties = append(properties, td.Properties[i])
+		}
+	}
+
+	td.Properties = properties
+
+	for i := range td.Composition {
+		if err := visitor.OnComposition(ctx, td, &td.Composition[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range td.DiscriminatorComponents {
+		if err := visitor.OnDiscriminator(ctx, td, &td.DiscriminatorComponents[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetExtension sets the extension _name_, e.g. `x-go-name`, of the _schema_ to _value_.
+func SetExtension(schema *openapi3.Schema, name string, value any) {
+	if schema.Extensions == nil {
+		schema.Extensions = map[string]any{}
+	}
+
+	schema.Extensions[name] = value
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mariotoffia/go-openapi/generator/gentypes"
+)
+
+// XMLNameField is the name of the struct field that holds the xml element name.
+const XMLNameField = "XMLName"
+
+// XMLTag renders the value of the `xml` struct tag of the _property_ of type _td_ from the
+// _xml_ objects of the schemas.
+//
+// The element is named by the _xml.name_ of an inline property schema, or of the referenced
+// struct, or the property name.
+// An _attribute_ is rendered as `,attr` and a _namespace_ as `<namespace> <name>`. Arrays are
+// repeated elements named by the _xml.name_ of the items, or the property name, and when
+// _wrapped_ they are enclosed in the element e.g. `photoUrls>photoUrl`.
+//
+// Maps can not be represented by `encoding/xml` and are rendered as `-`.
+//
+// NOTE: The _prefix_ is ignored since `encoding/xml` do not support prefixes.
+func XMLTag(property *gentypes.Property, td *gentypes.TypeDefinition) string {
+	if td.IsMap() {
+		return "-"
+	}
+
+	name := property.PropertyName
+	namespace := ""
+	attribute := false
+
+	var schema *openapi3.Schema
+	if property.Reference == nil {
+		schema = td.Schema
+	}
+
+	switch {
+	case schema != nil && schema.XML != nil:
+		if schema.XML.Name != "" {
+			name = schema.XML.Name
+		}
+
+		namespace = schema.XML.Namespace
+		attribute = schema.XML.Attribute
+	case td.IsObject():
+		// Must match the XMLName of the referenced struct
+		if ns, element, ok := xmlElementName(td); ok {
+			namespace, name = ns, element
+		}
+	}
+
+	if td.IsArray() && td.Schema != nil {
+		item := property.PropertyName
+		if items := td.Schema.Items; items != nil && items.Value != nil && items.Value.XML != nil {
+			if items.Value.XML.Name != "" {
+				item = items.Value.XML.Name
+			}
+
+			if namespace == "" {
+				namespace = items.Value.XML.Namespace
+			}
+		}
+
+		if td.Schema.XML != nil && td.Schema.XML.Wrapped {
+			name = name + ">" + item
+		} else {
+			name = item
+		}
+
+		attribute = false
+	}
+
+	tag := name
+	if namespace != "" {
+		tag = namespace + " " + name
+	}
+
+	if attribute {
+		tag += ",attr"
+	}
+
+	if !property.Required {
+		tag += ",omitempty"
+	}
+
+	return tag
+}
+
+// declareXMLName adds the `XMLName` field to the struct _decl_ when the schema of _td_ has
+// a _xml.name_ or _namespace_. It names the element both as root and when referenced by a
+// property, and when unmarshalled the element must have the name.
+func declareXMLName(file *GoFile, td *gentypes.TypeDefinition, decl *GoTypeDecl) {
+	namespace, name, ok := xmlElementName(td)
+	if !ok {
+		return
+	}
+
+	if namespace != "" {
+		name = namespace + " " + name
+	}
+
+	decl.Fields = append([]*GoField{{
+		Name: XMLNameField,
+		Type: file.Qualify("encoding/xml", "Name"),
+		Tag:  fmt.Sprintf(`json:"-" xml:%s`, strconv.Quote(name)),
+	}}, decl.Fields...)
+}
+
+// xmlElementName returns the namespace and element name of the struct _td_ when its schema
+// has a _xml.name_ or _namespace_. The name defaults to the type name.
+func xmlElementName(td *gentypes.TypeDefinition) (string, string, bool) {
+	if td.Schema == nil || td.Schema.XML == nil || (td.Schema.XML.Name == "" && td.Schema.XML.Namespace == "") {
+		return "", "", false
+	}
+
+	name := td.Schema.XML.Name
+	if name == "" {
+		name = td.ID.TypeName
+	}
+
+	return td.Schema.XML.Namespace, name, true
+}
+
+// xmlIgnore appends `xml:"-"` to the _tag_ when xml tags are rendered.
+func xmlIgnore(ctx *GeneratorContext, tag string) string {
+	if !ctx.settings.xml {
+		return tag
+	}
+
+	return strings.TrimSpace(tag + ` xml:"-"`)
+}
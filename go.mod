@@ -5,17 +5,20 @@ module github.com/mariotoffia/go-openapi
 require (
 	github.com/getkin/kin-openapi v0.110.0
 	github.com/gobwas/glob v0.2.3
-	github.com/stretchr/testify v1.8.1
 	github.com/iancoleman/strcase v0.2.0
+	github.com/invopop/yaml v0.1.0
+	github.com/stretchr/testify v1.8.1
 )
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/invopop/yaml v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
-	github.com/go-openapi/swag v0.19.5 // indirect	
-	github.com/invopop/yaml v0.1.0 // indirect
+	github.com/go-openapi/swag v0.19.5 // indirect
 	github.com/mailru/easyjson v0.0.0-20190626092158-b2ccc519800e // indirect
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
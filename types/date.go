@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the RFC 3339 _full-date_ layout used by the _OpenAPI_ `date` format.
+const DateLayout = "2006-01-02"
+
+// Date is a civil date without any time of day or location. It is the
+// go representation of the _OpenAPI_ `date` format.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the `Date` of _t_ in the location of _t_.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a RFC 3339 _full-date_ e.g. "2022-12-24".
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date: %s error: %s", s, err.Error())
+	}
+
+	return DateOf(t), nil
+}
+
+// String renders the date as RFC 3339 _full-date_.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero returns `true` when the date is the zero value.
+func (d Date) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// In returns the time at midnight of the date in the _loc_ location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// MarshalText implements `encoding.TextMarshaler`.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`.
+func (d *Date) UnmarshalText(data []byte) error {
+	date, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+
+	*d = date
+	return nil
+}
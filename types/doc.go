@@ -0,0 +1,3 @@
+// Package types contains the types that the generated go models depend on
+// at runtime, such as `Date` for the _OpenAPI_ `date` format.
+package types
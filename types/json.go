@@ -0,0 +1,92 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// UnmarshalStrict unmarshals the json _data_ into _v_. In contrast to `json.Unmarshal`
+// it fails when an object contains properties that are not declared in _v_.
+//
+// It is used to select which of several types a value matches.
+func UnmarshalStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after json value")
+	}
+
+	return nil
+}
+
+// MarshalMerged marshals each of the _values_ into a json object and merges them into a
+// single object. When several values have the same property, the last one wins. A value
+// that marshals into `null` is ignored.
+//
+// It is used to marshal a struct that is composed of several embedded structs.
+func MarshalMerged(values ...any) ([]byte, error) {
+	merged := map[string]json.RawMessage{}
+
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(data, []byte("null")) {
+			continue
+		}
+
+		properties := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &properties); err != nil {
+			return nil, fmt.Errorf("composed value is not a json object: %s", string(data))
+		}
+
+		for name, property := range properties {
+			merged[name] = property
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// UnmarshalMerged unmarshals the json object _data_ into each of the _values_.
+//
+// It is used to unmarshal a struct that is composed of several embedded structs.
+func UnmarshalMerged(data []byte, values ...any) error {
+	for _, value := range values {
+		if err := json.Unmarshal(data, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CanonicalJSON marshals _v_ into compact json where the properties of all objects are
+// sorted by name. When _v_ can not be marshalled, the error text is returned.
+//
+// It is used by the generated examples to print values independent of the field order.
+func CanonicalJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err.Error()
+	}
+
+	if data, err = json.Marshal(value); err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
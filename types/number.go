@@ -0,0 +1,19 @@
+package types
+
+import "math"
+
+// multipleOfTolerance is the relative tolerance of the quotient in `IsMultipleOf`.
+const multipleOfTolerance = 1e-9
+
+// IsMultipleOf returns `true` if _value_ is a multiple of _multiple_.
+//
+// In contrast to `math.Mod(value, multiple) == 0` it tolerates the rounding errors of
+// fractions that can not be represented exactly, e.g. 0.3 is a multiple of 0.1.
+func IsMultipleOf(value, multiple float64) bool {
+	if multiple == 0 {
+		return false
+	}
+
+	q := value / multiple
+	return math.Abs(q-math.Round(q)) <= multipleOfTolerance*math.Max(1, math.Abs(q))
+}
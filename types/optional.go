@@ -0,0 +1,168 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// null is the json null literal.
+var null = []byte("null")
+
+// Optional is a value that may be absent. It distinguishes an absent value from the zero
+// value of _T_, e.g. an empty string.
+//
+// A `null` in the json is treated as absent, the same way as for a pointer.
+//
+// NOTE: A struct containing a `Optional` needs to omit the absent values when marshalled,
+// see `OmitUnset`.
+type Optional[T any] struct {
+	// Value is the value when _Present_.
+	Value T
+	// Present is set when the value is present.
+	Present bool
+}
+
+// NewOptional creates a present `Optional` of _value_.
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Present: true}
+}
+
+// Get returns the value and if it is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// Set sets the _value_ and marks it present.
+func (o *Optional[T]) Set(value T) {
+	o.Value = value
+	o.Present = true
+}
+
+// IsZero returns `true` when the value is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// MarshalJSON marshals the value, or `null` when absent.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return null, nil
+	}
+
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON unmarshals the value and marks it present, unless it is `null`.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var value T
+
+	if bytes.Equal(bytes.TrimSpace(data), null) {
+		*o = Optional[T]{}
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = NewOptional(value)
+	return nil
+}
+
+// Nullable is a value that may be absent, `null` or a value of _T_. It is used when e.g.
+// a _PATCH_ needs to distinguish between leaving a property as is and clearing it.
+//
+// NOTE: A struct containing a `Nullable` needs to omit the absent values when marshalled,
+// see `OmitUnset`.
+type Nullable[T any] struct {
+	// Value is the value when _Present_ and not _Null_.
+	Value T
+	// Present is set when the value is present, i.e. `null` or a value.
+	Present bool
+	// Null is set when the value is present and `null`.
+	Null bool
+}
+
+// NewNullable creates a present, non `null`, `Nullable` of _value_.
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{Value: value, Present: true}
+}
+
+// NewNull creates a present `Nullable` that is `null`.
+func NewNull[T any]() Nullable[T] {
+	return Nullable[T]{Present: true, Null: true}
+}
+
+// Get returns the value and if it is present and not `null`.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Present && !n.Null
+}
+
+// Set sets the _value_ and marks it present and not `null`.
+func (n *Nullable[T]) Set(value T) {
+	*n = NewNullable(value)
+}
+
+// SetNull marks the value as present and `null`.
+func (n *Nullable[T]) SetNull() {
+	*n = NewNull[T]()
+}
+
+// IsZero returns `true` when the value is absent.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Present
+}
+
+// MarshalJSON marshals the value, or `null` when absent or `null`.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Present || n.Null {
+		return null, nil
+	}
+
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON unmarshals the value, or `null`, and marks it present.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	var value T
+
+	if bytes.Equal(bytes.TrimSpace(data), null) {
+		n.SetNull()
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	n.Set(value)
+	return nil
+}
+
+// OmitUnset removes the properties from the json object _data_ where _present_ is `false`.
+//
+// It is used by structs with `Optional` and `Nullable` fields since `omitempty` has no
+// effect on struct values.
+func OmitUnset(data []byte, present map[string]bool) ([]byte, error) {
+	omit := false
+	for _, ok := range present {
+		omit = omit || !ok
+	}
+
+	if !omit {
+		return data, nil
+	}
+
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+
+	for name, ok := range present {
+		if !ok {
+			delete(properties, name)
+		}
+	}
+
+	return json.Marshal(properties)
+}
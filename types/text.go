@@ -0,0 +1,15 @@
+package types
+
+import "encoding"
+
+// Text returns the text representation of _v_, e.g. the RFC 3339 representation of a
+// `time.Time`. It is used to validate the string constraints of types, such as `Date`,
+// that are not strings in go. An empty string is returned if _v_ can not be marshalled.
+func Text(v encoding.TextMarshaler) string {
+	text, err := v.MarshalText()
+	if err != nil {
+		return ""
+	}
+
+	return string(text)
+}
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRoundTrip(t *testing.T) {
+	var v struct {
+		Date Date `json:"date"`
+	}
+
+	err := json.Unmarshal([]byte(`{"date":"2022-12-24"}`), &v)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Date{Year: 2022, Month: time.December, Day: 24}, v.Date)
+
+	data, err := json.Marshal(v)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"date":"2022-12-24"}`, string(data))
+}
+
+func TestInvalidDateShallFail(t *testing.T) {
+	_, err := ParseDate("2022-13-24")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestUUIDRoundTrip(t *testing.T) {
+	u, err := ParseUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479", u.String())
+
+	data, err := json.Marshal(u)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `"f47ac10b-58cc-4372-a567-0e02b2c3d479"`, string(data))
+
+	_, err = ParseUUID("f47ac10b58cc-4372-a567-0e02b2c3d4799")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestUnmarshalStrictShallFailOnUnknownProperties(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+
+	assert.Equal(t, nil, UnmarshalStrict([]byte(`{"name":"a"}`), &v))
+	assert.Equal(t, "a", v.Name)
+	assert.NotEqual(t, nil, UnmarshalStrict([]byte(`{"name":"a","age":1}`), &v))
+	assert.NotEqual(t, nil, UnmarshalStrict([]byte(`{"name":"a"} {}`), &v))
+}
+
+func TestMarshalMergedShallMergeObjects(t *testing.T) {
+	type base struct {
+		ID string `json:"id"`
+	}
+
+	type own struct {
+		Name string `json:"name"`
+	}
+
+	data, err := MarshalMerged(base{ID: "1"}, nil, own{Name: "a"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":"1","name":"a"}`, string(data))
+
+	var b base
+	var o own
+	assert.Equal(t, nil, UnmarshalMerged(data, &b, &o))
+	assert.Equal(t, "1", b.ID)
+	assert.Equal(t, "a", o.Name)
+
+	_, err = MarshalMerged(base{ID: "1"}, []string{"a"})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCanonicalJSONShallSortProperties(t *testing.T) {
+	v := struct {
+		Name  string         `json:"name"`
+		Inner map[string]int `json:"inner"`
+		Age   int            `json:"age"`
+	}{Name: "a", Inner: map[string]int{"b": 2, "a": 1}, Age: 3}
+
+	assert.Equal(t, `{"age":3,"inner":{"a":1,"b":2},"name":"a"}`, CanonicalJSON(v))
+	assert.Equal(t, "json: unsupported type: chan int", CanonicalJSON(make(chan int)))
+}
+
+func TestNullableShallDistinguishAbsentNullAndValue(t *testing.T) {
+	type patch struct {
+		Name Nullable[string] `json:"name"`
+		Age  Optional[int]    `json:"age"`
+	}
+
+	var v patch
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"name":null}`), &v))
+	assert.Equal(t, NewNull[string](), v.Name)
+	assert.Equal(t, false, v.Age.Present)
+
+	v = patch{}
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"name":"a","age":0}`), &v))
+	assert.Equal(t, NewNullable("a"), v.Name)
+	assert.Equal(t, NewOptional(0), v.Age)
+
+	v = patch{}
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{}`), &v))
+	assert.Equal(t, false, v.Name.Present)
+
+	data, err := json.Marshal(patch{Name: NewNull[string]()})
+	assert.Equal(t, nil, err)
+
+	data, err = OmitUnset(data, map[string]bool{"name": true, "age": false})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"name":null}`, string(data))
+}
+
+func TestIsMultipleOfShallTolerateFractions(t *testing.T) {
+	assert.True(t, IsMultipleOf(0.3, 0.1))
+	assert.True(t, IsMultipleOf(19.99, 0.01))
+	assert.True(t, IsMultipleOf(-1.5, 0.5))
+	assert.True(t, IsMultipleOf(0, 0.1))
+	assert.False(t, IsMultipleOf(0.35, 0.1))
+	assert.False(t, IsMultipleOf(7, 5))
+	assert.False(t, IsMultipleOf(1, 0))
+}
+
+func TestTextShallRenderTheTextRepresentation(t *testing.T) {
+	assert.Equal(t, "2023-01-02T10:00:00Z", Text(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2023-01-02", Text(Date{Year: 2023, Month: 1, Day: 2}))
+	assert.Equal(t, "", Text(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a RFC 4122 universally unique identifier. It is the go
+// representation of the _OpenAPI_ `uuid` format.
+type UUID [16]byte
+
+// ParseUUID parses the canonical textual representation of a UUID,
+// e.g. "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid uuid: %s", s)
+	}
+
+	raw := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(u[:], []byte(raw)); err != nil {
+		return u, fmt.Errorf("invalid uuid: %s error: %s", s, err.Error())
+	}
+
+	return u, nil
+}
+
+// String renders the canonical textual representation of the UUID.
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf)
+}
+
+// IsZero returns `true` when the UUID is the nil UUID.
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// MarshalText implements `encoding.TextMarshaler`.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`.
+func (u *UUID) UnmarshalText(data []byte) error {
+	uuid, err := ParseUUID(string(data))
+	if err != nil {
+		return err
+	}
+
+	*u = uuid
+	return nil
+}